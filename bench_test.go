@@ -0,0 +1,158 @@
+package lw
+
+import (
+	"io/ioutil"
+	"log"
+	"testing"
+)
+
+// benchLevels are the message types being benchmarked.
+var benchLevels = []Level{LevelTrace, LevelDebug, LevelInfo, LevelWarning, LevelError}
+
+// benchf writes a printf-style log-entry of message type l.  The logging
+// functions are called directly rather than via function values, as the
+// latter would force their arguments onto the heap.
+func benchf(l Level, i int) {
+	switch l {
+	case LevelTrace:
+		Tracef("request %d served in %dms", i, 42)
+	case LevelDebug:
+		Debugf("request %d served in %dms", i, 42)
+	case LevelInfo:
+		Infof("request %d served in %dms", i, 42)
+	case LevelWarning:
+		Warningf("request %d served in %dms", i, 42)
+	case LevelError:
+		Errorf("request %d served in %dms", i, 42)
+	}
+}
+
+// benchw writes a structured log-entry of message type l.
+func benchw(l Level, i int) {
+	switch l {
+	case LevelTrace:
+		Tracew("request served", "id", i, "ms", 42, "path", "/api/v1/users")
+	case LevelDebug:
+		Debugw("request served", "id", i, "ms", 42, "path", "/api/v1/users")
+	case LevelInfo:
+		Infow("request served", "id", i, "ms", 42, "path", "/api/v1/users")
+	case LevelWarning:
+		Warningw("request served", "id", i, "ms", 42, "path", "/api/v1/users")
+	case LevelError:
+		Errorw("request served", "id", i, "ms", 42, "path", "/api/v1/users")
+	}
+}
+
+// benchInit configures lw to write all message types to ioutil.Discard.
+func benchInit(enabled, loc, color bool) {
+	InitWithSettings(LogWriterState{
+		Enabled:        enabled,
+		LocEnabled:     loc,
+		ColorEnabled:   color,
+		ColorMode:      ColorAlways,
+		TraceEnabled:   true,
+		DebugEnabled:   true,
+		InfoEnabled:    true,
+		WarningEnabled: true,
+		ErrorEnabled:   true,
+		LocMode:        LocShort,
+	}, ioutil.Discard)
+}
+
+// BenchmarkLevels measures every message type in each of the supported
+// configurations.  Run with -benchmem or compare against
+// BenchmarkStdlogDiscard to check the claims made in the README.
+func BenchmarkLevels(b *testing.B) {
+	defer DisableAndReset()
+	for _, lv := range benchLevels {
+		lv := lv
+		b.Run(lv.String()+"/Disabled", func(b *testing.B) {
+			benchInit(false, false, false)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchf(lv, i)
+			}
+		})
+		b.Run(lv.String()+"/Enabled", func(b *testing.B) {
+			benchInit(true, false, false)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchf(lv, i)
+			}
+		})
+		b.Run(lv.String()+"/WithLoc", func(b *testing.B) {
+			benchInit(true, true, false)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchf(lv, i)
+			}
+		})
+		b.Run(lv.String()+"/Color", func(b *testing.B) {
+			benchInit(true, false, true)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchf(lv, i)
+			}
+		})
+		b.Run(lv.String()+"/Structured", func(b *testing.B) {
+			benchInit(true, false, false)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchw(lv, i)
+			}
+		})
+		b.Run(lv.String()+"/Parallel", func(b *testing.B) {
+			benchInit(true, false, false)
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					benchf(lv, i)
+					i++
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkLoggerWith measures a derived Logger carrying a name and
+// fields.
+func BenchmarkLoggerWith(b *testing.B) {
+	defer DisableAndReset()
+	benchInit(true, false, false)
+	lg := Named("api").With("version", 2)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lg.Infow("request served", "id", i)
+	}
+}
+
+// BenchmarkStatic measures messages without interface-typed arguments,
+// which are written without allocating.
+func BenchmarkStatic(b *testing.B) {
+	defer DisableAndReset()
+	benchInit(true, true, false)
+	b.Run("Info", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Info("request served")
+		}
+	})
+	b.Run("Infow", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Infow("request served", "path", "/api/v1/users", "ok", true)
+		}
+	})
+}
+
+// BenchmarkStdlogDiscard is the baseline: the standard library logger
+// writing to ioutil.Discard.
+func BenchmarkStdlogDiscard(b *testing.B) {
+	l := log.New(ioutil.Discard, "INFO: ", log.LstdFlags)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Printf("request %d served in %dms", i, 42)
+	}
+}
@@ -0,0 +1,84 @@
+// Command lwaudit verifies audit logs written by package lwaudit.  The key
+// is read from the file named by -keyfile, or else from the LW_AUDIT_KEY
+// environment variable.  A single trailing "\n" or "\r\n" is removed from
+// the key file, so that a file written by echo holds the same key as the
+// variable.  For each file, the number of entries that verified is
+// reported, along with the first broken or missing entry.  The exit status
+// is 1 if any file fails verification.
+//
+// Usage:
+//
+//	lwaudit [-keyfile file] auditlog...
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/1414C/lw/lwaudit"
+)
+
+func main() {
+	keyFile := flag.String("keyfile", "", "read the key from `file` instead of $LW_AUDIT_KEY")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: lwaudit [-keyfile file] auditlog...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	key := []byte(os.Getenv("LW_AUDIT_KEY"))
+	if *keyFile != "" {
+		var err error
+		if key, err = readKey(*keyFile); err != nil {
+			fmt.Fprintln(os.Stderr, "lwaudit:", err)
+			os.Exit(2)
+		}
+	}
+	if len(key) == 0 {
+		fmt.Fprintln(os.Stderr, "lwaudit: no key, use -keyfile or set LW_AUDIT_KEY")
+		os.Exit(2)
+	}
+
+	status := 0
+	for _, name := range flag.Args() {
+		if err := verify(name, key); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			status = 1
+		}
+	}
+	os.Exit(status)
+}
+
+// readKey returns the key held in file name, without a trailing "\n" or
+// "\r\n".
+func readKey(name string) ([]byte, error) {
+	key, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasSuffix(key, []byte("\n")) {
+		return key, nil
+	}
+	key = key[:len(key)-1]
+	return bytes.TrimSuffix(key, []byte("\r")), nil
+}
+
+// verify verifies audit log file name, reporting the number of entries
+// that verified.
+func verify(name string, key []byte) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	n, err := lwaudit.Verify(f, key)
+	fmt.Printf("%s: %d entries verified\n", name, n)
+	return err
+}
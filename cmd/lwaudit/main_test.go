@@ -0,0 +1,26 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadKey(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "key")
+	for _, content := range []string{"secret", "secret\n", "secret\r\n"} {
+		if err := ioutil.WriteFile(name, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		key, err := readKey(name)
+		if err != nil || string(key) != "secret" {
+			t.Errorf("%q: got %q, %v", content, key, err)
+		}
+	}
+	for content, want := range map[string]string{"secret\n\n": "secret\n", "secret\r": "secret\r"} {
+		ioutil.WriteFile(name, []byte(content), 0600)
+		if key, _ := readKey(name); string(key) != want {
+			t.Errorf("%q: got %q, want %q", content, key, want)
+		}
+	}
+}
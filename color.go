@@ -0,0 +1,183 @@
+package lw
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorMode determines when colored output is written once coloring has
+// been enabled via ColorEnable.
+type ColorMode int
+
+const (
+	// ColorAuto colors the output only if the writer is a terminal
+	// (default).  A non-empty NO_COLOR environment variable disables the
+	// colors, while a non-empty FORCE_COLOR environment variable other than
+	// "0" or "false" forces them.  FORCE_COLOR takes precedence.
+	ColorAuto ColorMode = iota
+	// ColorAlways colors the output regardless of the writer and the
+	// environment.
+	ColorAlways
+)
+
+// Color is an ANSI foreground color, held as the parameters of its SGR
+// escape sequence.  The zero value leaves the color unchanged.
+type Color string
+
+// The 16 standard terminal colors.
+const (
+	ColorBlack         Color = "30"
+	ColorRed           Color = "31"
+	ColorGreen         Color = "32"
+	ColorYellow        Color = "33"
+	ColorBlue          Color = "34"
+	ColorMagenta       Color = "35"
+	ColorCyan          Color = "36"
+	ColorWhite         Color = "37"
+	ColorBrightBlack   Color = "90"
+	ColorBrightRed     Color = "91"
+	ColorBrightGreen   Color = "92"
+	ColorBrightYellow  Color = "93"
+	ColorBrightBlue    Color = "94"
+	ColorBrightMagenta Color = "95"
+	ColorBrightCyan    Color = "96"
+	ColorBrightWhite   Color = "97"
+)
+
+// Color256 returns color n of the 256 color palette.
+func Color256(n uint8) Color {
+	return Color("38;5;" + strconv.Itoa(int(n)))
+}
+
+// ColorRGB returns the 24-bit truecolor with components r, g and b.
+func ColorRGB(r, g, b uint8) Color {
+	return Color("38;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b)))
+}
+
+// Style is the rendering of a message type.
+type Style struct {
+	Color Color
+	Bold  bool
+	Dim   bool
+}
+
+// sgr returns the escape sequence selecting style s, or an empty string if
+// s is the default style.
+func (s Style) sgr() string {
+	p := make([]string, 0, 3)
+	if s.Color != "" {
+		p = append(p, string(s.Color))
+	}
+	if s.Bold {
+		p = append(p, "1")
+	}
+	if s.Dim {
+		p = append(p, "2")
+	}
+	if len(p) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(p, ";") + "m"
+}
+
+// ansiReset restores the default rendering.
+const ansiReset = "\x1b[0m"
+
+// Theme holds the Style of each message type.
+type Theme struct {
+	Trace   Style
+	Debug   Style
+	Info    Style
+	Warning Style
+	Error   Style
+	Fatal   Style
+
+	// Line applies the Style to the whole first line of a log-entry
+	// rather than only to its label.
+	Line bool
+}
+
+// DefaultTheme returns the Theme used unless another one is set.
+func DefaultTheme() Theme {
+	return Theme{
+		Trace:   Style{Color: Color256(13)},
+		Debug:   Style{Color: Color256(213)},
+		Info:    Style{Color: ColorGreen, Bold: true},
+		Warning: Style{Color: Color256(11)},
+		Error:   Style{Color: Color256(9)},
+		Fatal:   Style{Color: Color256(9)},
+	}
+}
+
+// SetColorMode sets when colored output is written.
+// Usage Example:
+// lw.SetColorMode(lw.ColorAlways)
+func SetColorMode(m ColorMode) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.colorMode = m
+	setLabels()
+}
+
+// SetTheme sets the Theme used for colored output.
+// Usage Example:
+// t := lw.DefaultTheme()
+// t.Info = lw.Style{Color: lw.ColorRGB(0, 175, 255)}
+// lw.SetTheme(t)
+func SetTheme(t Theme) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.theme = t
+	setLabels()
+}
+
+// useColor reports whether the output is to be colored.  logWriter.mu must
+// be held.
+func useColor() bool {
+	if !logWriter.colorEnabled {
+		return false
+	}
+	if logWriter.colorMode == ColorAlways {
+		return true
+	}
+	if f := os.Getenv("FORCE_COLOR"); f != "" {
+		return f != "0" && f != "false"
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(logWriter.writer)
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// paint renders label l in Style s if color is set.  When the whole line is
+// colored, the Style is reset at the end of the line instead.
+func paint(color bool, s Style, l string) string {
+	sgr := s.sgr()
+	if !color || sgr == "" || (l == "" && !logWriter.lineColor) {
+		return l
+	}
+	if logWriter.lineColor {
+		return sgr + l
+	}
+	return sgr + l + ansiReset
+}
+
+// eol returns the end of the first line of a log-entry.
+func eol() string {
+	if logWriter.lineColor {
+		return ansiReset + "\n"
+	}
+	return "\n"
+}
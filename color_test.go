@@ -0,0 +1,91 @@
+package lw
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestColorAuto(t *testing.T) {
+	defer DisableAndReset()
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("NO_COLOR", "")
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{Enabled: true, InfoEnabled: true, ColorEnabled: true, TimeFormat: TimeFormatNone}, &buf)
+
+	Info("plain")
+	if want := "INFO:\tplain\n"; buf.String() != want {
+		t.Errorf("expected no colors for a buffer, got %q", buf.String())
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if isTerminal(f) {
+		t.Error("expected a regular file not to be a terminal")
+	}
+
+	tests := []struct {
+		force, no string
+		mode      ColorMode
+		want      string
+	}{
+		{"1", "", ColorAuto, "\x1b[32;1mINFO:\t\x1b[0mforced\n"},
+		{"1", "1", ColorAuto, "\x1b[32;1mINFO:\t\x1b[0mforced\n"},
+		{"0", "", ColorAuto, "INFO:\tforced\n"},
+		{"", "1", ColorAlways, "\x1b[32;1mINFO:\t\x1b[0mforced\n"},
+	}
+	for _, tt := range tests {
+		t.Setenv("FORCE_COLOR", tt.force)
+		t.Setenv("NO_COLOR", tt.no)
+		buf.Reset()
+		SetColorMode(tt.mode)
+		Info("forced")
+		if buf.String() != tt.want {
+			t.Errorf("FORCE_COLOR=%q NO_COLOR=%q mode %d: got %q, want %q", tt.force, tt.no, tt.mode, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestTheme(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		InfoEnabled:    true,
+		WarningEnabled: true,
+		ErrorEnabled:   true,
+		ColorEnabled:   true,
+		ColorMode:      ColorAlways,
+		TimeFormat:     TimeFormatNone,
+	}, &buf)
+
+	th := DefaultTheme()
+	th.Info = Style{Color: ColorRGB(0, 175, 255), Bold: true}
+	th.Warning = Style{Color: ColorBrightYellow, Dim: true}
+	th.Error = Style{}
+	SetTheme(th)
+	Info("rgb")
+	Warning("dim")
+	Errorf("default")
+	want := "\x1b[38;2;0;175;255;1mINFO:\t\x1b[0mrgb\n" +
+		"\x1b[93;2mWARNING:\t\x1b[0mdim\n" +
+		"ERROR:\tdefault\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	th.Line = true
+	SetTheme(th)
+	Infow("line", "k", "v")
+	if want := "\x1b[38;2;0;175;255;1mINFO:\tline k=v\x1b[0m\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if s := GetState(); s.Theme == nil || !s.Theme.Line || s.ColorMode != ColorAlways {
+		t.Errorf("unexpected state %+v", s)
+	}
+}
@@ -0,0 +1,153 @@
+package lw
+
+import "context"
+
+// ctxKey is the type of the keys used to store lw values in a context.
+type ctxKey int
+
+const (
+	loggerKey ctxKey = iota
+	fieldsKey
+	requestIDKey
+	traceIDKey
+)
+
+// NewContext returns a copy of ctx carrying Logger l.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// FromContext returns the Logger carried by ctx.  If ctx does not carry a
+// Logger, a Logger behaving like the package-level functions is returned.
+func FromContext(ctx context.Context) *Logger {
+	if ctx == nil {
+		return std
+	}
+	if l, ok := ctx.Value(loggerKey).(*Logger); ok && l != nil {
+		return l
+	}
+	return std
+}
+
+// ContextWithRequestID returns a copy of ctx carrying request ID id.  The
+// request ID is written as the field request_id by the *Ctx functions.
+// Usage Example:
+// r = r.WithContext(lw.ContextWithRequestID(r.Context(), uuid))
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// ContextWithTraceID returns a copy of ctx carrying trace ID id.  The trace
+// ID is written as the field trace_id by the *Ctx functions.
+func ContextWithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceIDKey, id)
+}
+
+// ContextWithFields returns a copy of ctx carrying the alternating keys and
+// values in kv in addition to any fields already carried by ctx.  The
+// fields are written by the *Ctx functions.
+func ContextWithFields(ctx context.Context, kv ...interface{}) context.Context {
+	old, _ := ctx.Value(fieldsKey).([]interface{})
+	f := make([]interface{}, 0, len(old)+len(kv))
+	f = append(f, old...)
+	f = append(f, kv...)
+	return context.WithValue(ctx, fieldsKey, f)
+}
+
+// contextFields returns the request ID, trace ID and fields carried by ctx
+// as alternating keys and values.
+func contextFields(ctx context.Context) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+	var f []interface{}
+	if id, ok := ctx.Value(requestIDKey).(string); ok {
+		f = append(f, "request_id", id)
+	}
+	if id, ok := ctx.Value(traceIDKey).(string); ok {
+		f = append(f, "trace_id", id)
+	}
+	if kv, ok := ctx.Value(fieldsKey).([]interface{}); ok {
+		f = append(f, kv...)
+	}
+	return f
+}
+
+// logctx writes a log-entry with message s followed by the fields carried
+// by ctx and the key/value pairs in kv.
+func logctx(calldepth int, lg *Logger, l Level, ctx context.Context, s string, kv ...interface{}) {
+	if enabled(lg, l) && admit(calldepth+1, lg, l, s) {
+		output(calldepth+1, lg, l, s, append(contextFields(ctx), kv...), "")
+	}
+}
+
+// TraceCtx writes a Trace message using the Logger carried by ctx.  The message is
+// followed by the request ID, trace ID and fields carried by ctx, and the list of
+// alternating keys and values in kv.
+// Usage Example:
+// lw.TraceCtx(r.Context(), "Entered handler", "path", r.URL.Path)
+func TraceCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, FromContext(ctx), LevelTrace, ctx, s, kv...)
+}
+
+// DebugCtx writes a Debug message using the Logger carried by ctx.  See TraceCtx.
+func DebugCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, FromContext(ctx), LevelDebug, ctx, s, kv...)
+}
+
+// InfoCtx writes an Info message using the Logger carried by ctx.  See TraceCtx.
+func InfoCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, FromContext(ctx), LevelInfo, ctx, s, kv...)
+}
+
+// WarningCtx writes a Warning message using the Logger carried by ctx.  See TraceCtx.
+func WarningCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, FromContext(ctx), LevelWarning, ctx, s, kv...)
+}
+
+// ErrorCtx writes an Error message using the Logger carried by ctx.  See TraceCtx.
+func ErrorCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, FromContext(ctx), LevelError, ctx, s, kv...)
+}
+
+// FatalCtx writes a Fatal log-entry using the Logger carried by ctx and then
+// terminates the application via os.Exit(1).  See TraceCtx.
+func FatalCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, FromContext(ctx), LevelFatal, ctx, s, kv...)
+}
+
+// TraceCtx writes a Trace message followed by the fields carried by ctx.
+// See the package-level TraceCtx function.
+func (l *Logger) TraceCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, l, LevelTrace, ctx, s, kv...)
+}
+
+// DebugCtx writes a Debug message followed by the fields carried by ctx.
+// See the package-level TraceCtx function.
+func (l *Logger) DebugCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, l, LevelDebug, ctx, s, kv...)
+}
+
+// InfoCtx writes an Info message followed by the fields carried by ctx.
+// See the package-level TraceCtx function.
+func (l *Logger) InfoCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, l, LevelInfo, ctx, s, kv...)
+}
+
+// WarningCtx writes a Warning message followed by the fields carried by ctx.
+// See the package-level TraceCtx function.
+func (l *Logger) WarningCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, l, LevelWarning, ctx, s, kv...)
+}
+
+// ErrorCtx writes an Error message followed by the fields carried by ctx.
+// See the package-level TraceCtx function.
+func (l *Logger) ErrorCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, l, LevelError, ctx, s, kv...)
+}
+
+// FatalCtx writes a Fatal log-entry followed by the fields carried by ctx
+// and terminates the application.  See the package-level TraceCtx function.
+func (l *Logger) FatalCtx(ctx context.Context, s string, kv ...interface{}) {
+	logctx(2, l, LevelFatal, ctx, s, kv...)
+}
@@ -0,0 +1,55 @@
+package lw
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:     true,
+		LocEnabled:  true,
+		InfoEnabled: true,
+		TimeFormat:  TimeFormatNone,
+		LocMode:     LocShort,
+	}, &buf)
+
+	if FromContext(context.Background()) == nil {
+		t.Fatal("expected a default Logger")
+	}
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = ContextWithTraceID(ctx, "trace-1")
+	ctx = ContextWithFields(ctx, "user", 42)
+	ctx = ContextWithFields(ctx, "tenant", "acme")
+
+	_, line := nextLine()
+	InfoCtx(ctx, "login ok", "method", "password")
+	want := "INFO:\tlogin ok request_id=req-1 trace_id=trace-1 user=42 tenant=acme method=password" +
+		"\tcontext_test.go line:" + strconv.Itoa(line) + "\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// the Logger carried by the context determines the caller location
+	buf.Reset()
+	ctx = NewContext(ctx, WithCallerSkip(1))
+	helper := func() { InfoCtx(ctx, "from helper") }
+	_, line = nextLine()
+	helper()
+	want = "INFO:\tfrom helper request_id=req-1 trace_id=trace-1 user=42 tenant=acme" +
+		"\tcontext_test.go line:" + strconv.Itoa(line) + "\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	WarningCtx(ctx, "disabled")
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
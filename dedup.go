@@ -0,0 +1,83 @@
+package lw
+
+import (
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// dedupKey identifies a log-entry for duplicate detection: its message
+// type, its message including fields, and its caller.
+type dedupKey struct {
+	l    Level
+	m    string
+	file string
+	line int
+}
+
+// deduper collapses consecutive identical log-entries.
+type deduper struct {
+	mu    sync.Mutex
+	key   dedupKey
+	last  time.Time
+	count int
+}
+
+var dedup deduper
+
+// SetDedup enables the suppression of duplicate log-entries.  A log-entry
+// identical to the previous one, sharing its message type, message, fields
+// and caller, and written within window of it, is not written.  Instead,
+// the number of such repeats is reported by a "last message repeated N
+// times" log-entry, written ahead of the next log-entry that is not
+// suppressed or by Flush.  If the log goes quiet the count is not written
+// until then, so use FlushEvery to have it written periodically.  A window
+// <= 0 disables duplicate suppression.
+// Usage Example:
+// lw.SetDedup(10 * time.Second)
+func SetDedup(window time.Duration) {
+	dedup.flush()
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.dedupWindow = window
+}
+
+// Flush writes all pending summary log-entries, reporting messages that
+// were dropped by sampling or collapsed by duplicate suppression.
+func Flush() {
+	dedup.flush()
+	FlushSuppressed()
+}
+
+// write writes log-entry b identified by k, unless it repeats the previous
+// log-entry within the dedup window.
+func (d *deduper) write(k dedupKey, b []byte) {
+	t := now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if k == d.key && k.l != LevelFatal && t.Sub(d.last) < logWriter.dedupWindow {
+		d.count++
+		return
+	}
+	d.report()
+	logWriter.writer.Write(b)
+	d.key, d.last, d.count = k, t, 0
+}
+
+// flush writes the pending repeat count, if any, and forgets the previous
+// log-entry.
+func (d *deduper) flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.report()
+	d.key = dedupKey{}
+}
+
+// report writes the pending repeat count, if any.  d.mu must be held.
+func (d *deduper) report() {
+	if d.count > 0 {
+		io.WriteString(logWriter.writer, label(d.key.l)+timestamp(now())+"last message repeated "+strconv.Itoa(d.count)+" times"+eol())
+	}
+	d.count = 0
+}
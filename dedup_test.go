@@ -0,0 +1,84 @@
+package lw
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestDedup(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	clock := time.Unix(0, 0)
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		WarningEnabled: true,
+		ErrorEnabled:   true,
+		TimeFormat:     TimeFormatNone,
+		Clock:          func() time.Time { return clock },
+		DedupWindow:    time.Minute,
+	}, &buf)
+
+	flap := func() { Warningw("dependency down", "host", "db1") }
+	for i := 0; i < 5; i++ {
+		flap()
+	}
+	Errorf("other")
+	want := "WARNING:\tdependency down host=db1\n" +
+		"WARNING:\tlast message repeated 4 times\n" +
+		"ERROR:\tother\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// identical messages from another caller are not collapsed
+	buf.Reset()
+	flap()
+	Warningw("dependency down", "host", "db1")
+	if want := "WARNING:\tdependency down host=db1\nWARNING:\tdependency down host=db1\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// repeats after the window has passed are written again
+	buf.Reset()
+	flap()
+	flap()
+	clock = clock.Add(time.Minute)
+	flap()
+	flap()
+	Flush()
+	want = "WARNING:\tdependency down host=db1\n" +
+		"WARNING:\tlast message repeated 1 times\n" +
+		"WARNING:\tdependency down host=db1\n" +
+		"WARNING:\tlast message repeated 1 times\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDedupFlushEvery(t *testing.T) {
+	defer DisableAndReset()
+	w := make(chanWriter, 4)
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		WarningEnabled: true,
+		TimeFormat:     TimeFormatNone,
+		DedupWindow:    time.Hour,
+	}, w)
+
+	for i := 0; i < 3; i++ {
+		Warning("dependency down")
+	}
+	stop := FlushEvery(time.Millisecond)
+	defer stop()
+	for _, want := range []string{"WARNING:\tdependency down\n", "WARNING:\tlast message repeated 2 times\n"} {
+		select {
+		case got := <-w:
+			if got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+}
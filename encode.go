@@ -0,0 +1,112 @@
+package lw
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxPooledBuffer is the capacity above which buffers are not returned to
+// the pool, so that a single large log-entry does not pin its memory.
+const maxPooledBuffer = 64 << 10
+
+// encoder holds the reusable memory needed to build and render a Record:
+// the buffer the log-entry is rendered into and the backing array of its
+// Fields.
+type encoder struct {
+	buf    []byte
+	fields []Field
+}
+
+var encoderPool = sync.Pool{
+	New: func() interface{} {
+		return &encoder{buf: make([]byte, 0, 512), fields: make([]Field, 0, 8)}
+	},
+}
+
+// getEncoder returns an empty encoder from the pool.
+func getEncoder() *encoder {
+	e := encoderPool.Get().(*encoder)
+	e.buf = e.buf[:0]
+	e.fields = e.fields[:0]
+	return e
+}
+
+// putEncoder returns encoder e to the pool, releasing the field values it
+// refers to.
+func putEncoder(e *encoder) {
+	if cap(e.buf) > maxPooledBuffer {
+		return
+	}
+	for i := range e.fields {
+		e.fields[i] = Field{}
+	}
+	encoderPool.Put(e)
+}
+
+// appendRecord appends the rendered Record r to b, and returns the extended
+// buffer along with the offsets of the text of r within it.
+func appendRecord(b []byte, r *Record) ([]byte, int, int) {
+	b = append(b, label(r.Level)...)
+	b = appendTimestamp(b, r.Time)
+	start := len(b)
+	b = r.appendText(b)
+	end := len(b)
+	b = appendLocation(b, r.Caller)
+	if r.detail == "" && r.stack == "" {
+		return append(b, eol()...), start, end
+	}
+	return appendContinuation(b, r.detail+r.stack), start, end
+}
+
+// appendFieldsText appends fields f to b as a space-separated list of key=value
+// pairs, including the leading space.
+func appendFieldsText(b []byte, f []Field) []byte {
+	for _, fl := range f {
+		b = append(b, ' ')
+		b = appendLines(b, fl.Key)
+		b = append(b, '=')
+		b = appendValue(b, fl.Value)
+	}
+	return b
+}
+
+// appendValue appends the value v of a field to b, rendered in the manner
+// of fmt.Sprint with the MultiLineMode applied.  Common types are appended
+// directly without allocating.
+func appendValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return appendLines(b, v)
+	case int:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int8:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int16:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int32:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case uint:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint8:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint16:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint32:
+		return strconv.AppendUint(b, uint64(v), 10)
+	case uint64:
+		return strconv.AppendUint(b, v, 10)
+	case float32:
+		return strconv.AppendFloat(b, float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.AppendFloat(b, v, 'g', -1, 64)
+	case bool:
+		return strconv.AppendBool(b, v)
+	case time.Duration:
+		return append(b, v.String()...)
+	}
+	return appendLines(b, fmt.Sprint(v))
+}
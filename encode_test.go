@@ -0,0 +1,52 @@
+package lw
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAppendValue(t *testing.T) {
+	values := []interface{}{
+		"text", "", 0, -42, int8(-8), int16(1600), int32(-32000), int64(math.MinInt64),
+		uint(7), uint8(255), uint16(65535), uint32(1 << 31), uint64(math.MaxUint64),
+		float32(0.1), 1.5, 1e21, 1e-7, 100000000.0, math.Inf(-1), math.NaN(),
+		true, false, 1500 * time.Millisecond, errors.New("boom"), nil, []int{1, 2},
+		struct{ A int }{3},
+	}
+	for _, v := range values {
+		if got, want := string(appendValue(nil, v)), fmt.Sprint(v); got != want {
+			t.Errorf("appendValue(%#v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestZeroAlloc(t *testing.T) {
+	defer DisableAndReset()
+	InitWithSettings(LogWriterState{
+		Enabled:     true,
+		LocEnabled:  true,
+		InfoEnabled: true,
+		LocMode:     LocShort,
+	}, ioutil.Discard)
+	lg := Named("api").With("version", "v2")
+
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"Info", func() { Info("request served") }},
+		{"Infof", func() { Infof("request served") }},
+		{"Infow", func() { Infow("request served", "path", "/api/v1/users", "ok", true) }},
+		{"LoggerInfow", func() { lg.Infow("request served", "path", "/api/v1/users") }},
+		{"Disabled", func() { Debugf("request %d served", 1234567) }},
+	}
+	for _, tt := range tests {
+		if n := testing.AllocsPerRun(100, tt.f); n != 0 {
+			t.Errorf("%s: got %v allocations per log-entry, want 0", tt.name, n)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package lw
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrorFielder is an optional interface for errors carrying structured
+// fields.  When error detail is enabled the fields are written alongside
+// the error message.
+type ErrorFielder interface {
+	LogFields() map[string]interface{}
+}
+
+// multiError is implemented by errors wrapping more than one error, such
+// as those returned by errors.Join.
+type multiError interface {
+	Unwrap() []error
+}
+
+// ErrorDetailEnable enables/disables detailed error rendering for Error,
+// ErrorWithPrefixString and Fatal log-entries.  When enabled, the concrete
+// error type and any fields provided via ErrorFielder are added to the
+// message, and each error in the wrapped chain is written on the lines
+// following the log-entry.
+func ErrorDetailEnable(a bool) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.errorDetail = a
+}
+
+// errorText renders error e for a log-entry.  It returns the message to be
+// used on the first line of the log-entry, and the rendered causes to be
+// written on the following lines.  A nil error, or a nil pointer whose
+// Error method panics, is rendered as "<nil>".
+func errorText(e error) (string, string) {
+	if e == nil {
+		return "<nil>", ""
+	}
+	if !logWriter.errorDetail {
+		return errString(e), ""
+	}
+	var b strings.Builder
+	writeCauses(&b, e, 1)
+	return errorDetail(e), b.String()
+}
+
+// errorDetail returns the message of e followed by its type and fields.
+func errorDetail(e error) string {
+	s := errString(e) + " type=" + fmt.Sprintf("%T", e)
+	if f, ok := e.(ErrorFielder); ok && !nilPointer(e) {
+		s += fieldText(f.LogFields())
+	}
+	return s
+}
+
+// errString returns the message of e, recovering from a panic of its
+// Error method in the manner of fmt.  A nil pointer receiver is rendered
+// as "<nil>".
+func errString(e error) (s string) {
+	defer func() {
+		if v := recover(); v != nil {
+			if nilPointer(e) {
+				s = "<nil>"
+				return
+			}
+			s = fmt.Sprintf("%%!v(PANIC=Error method: %v)", v)
+		}
+	}()
+	return e.Error()
+}
+
+// nilPointer reports whether e holds a nil pointer.
+func nilPointer(e error) bool {
+	v := reflect.ValueOf(e)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// writeCauses writes each error wrapped by e to b, one per line.  A linear
+// chain is written at the same indentation, while the branches of a multi
+// error are indented beneath it.
+func writeCauses(b *strings.Builder, e error, indent int) {
+	for {
+		if nilPointer(e) {
+			return
+		}
+		if m, ok := e.(multiError); ok {
+			for _, c := range m.Unwrap() {
+				if c == nil {
+					continue
+				}
+				b.WriteString(strings.Repeat("\t", indent) + "cause: " + errorDetail(c) + "\n")
+				writeCauses(b, c, indent+1)
+			}
+			return
+		}
+		e = errors.Unwrap(e)
+		if e == nil {
+			return
+		}
+		b.WriteString(strings.Repeat("\t", indent) + "cause: " + errorDetail(e) + "\n")
+	}
+}
+
+// fieldText renders map m as a space-separated list of key=value pairs in
+// key order, including the leading space.
+func fieldText(m map[string]interface{}) string {
+	if len(m) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		if redactField(k) {
+			b.WriteString(" " + k + "=" + Redacted)
+			continue
+		}
+		b.WriteString(" " + k + "=")
+		b.Write(appendValue(nil, m[k]))
+	}
+	return b.String()
+}
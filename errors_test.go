@@ -0,0 +1,113 @@
+package lw
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type fieldsError struct {
+	code int
+}
+
+func (e fieldsError) Error() string {
+	return "request failed"
+}
+
+func (e fieldsError) LogFields() map[string]interface{} {
+	return map[string]interface{}{"status": e.code, "retry": false}
+}
+
+type joinedError []error
+
+func (e joinedError) Error() string {
+	return "multiple errors"
+}
+
+func (e joinedError) Unwrap() []error {
+	return e
+}
+
+type ptrError struct {
+	msg   string
+	cause error
+}
+
+func (e *ptrError) Error() string {
+	return e.msg
+}
+
+func (e *ptrError) Unwrap() error {
+	return e.cause
+}
+
+func (e *ptrError) LogFields() map[string]interface{} {
+	return map[string]interface{}{"msg": e.msg}
+}
+
+func TestErrorNil(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	Enable(false, false, &buf)
+	ErrorEnable(true)
+	SetTimeFormat(TimeFormatNone)
+
+	Error(nil)
+	ErrorWithPrefixString("prefix", nil)
+	ErrorDetailEnable(true)
+	Error(nil)
+	want := "ERROR:\t<nil>\nERROR:\tprefix <nil>\nERROR:\t<nil>\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// a typed nil is rendered as "<nil>" rather than panicking
+	buf.Reset()
+	var pe *ptrError
+	ErrorDetailEnable(false)
+	Error(pe)
+	ErrorDetailEnable(true)
+	Error(pe)
+	Error(fmt.Errorf("load: %w", pe))
+	want = "ERROR:\t<nil>\n" +
+		"ERROR:\t<nil> type=*lw.ptrError\n" +
+		"ERROR:\tload: <nil> type=*fmt.wrapError\n\tcause: <nil> type=*lw.ptrError\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestErrorDetail(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	Enable(false, false, &buf)
+	ErrorEnable(true)
+	SetTimeFormat(TimeFormatNone)
+
+	e := fmt.Errorf("load user: %w", fieldsError{code: 503})
+	Error(e)
+	if want := "ERROR:\tload user: request failed\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	ErrorDetailEnable(true)
+	Error(e)
+	want := "ERROR:\tload user: request failed type=*fmt.wrapError\n" +
+		"\tcause: request failed type=lw.fieldsError retry=false status=503\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	m := joinedError{errors.New("first"), fmt.Errorf("second: %w", errors.New("inner"))}
+	ErrorWithPrefixString("prefix", m)
+	want = "ERROR:\tprefix multiple errors type=lw.joinedError\n" +
+		"\tcause: first type=*errors.errorString\n" +
+		"\tcause: second: inner type=*fmt.wrapError\n" +
+		"\t\tcause: inner type=*errors.errorString\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
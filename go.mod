@@ -1,3 +1,5 @@
 module github.com/1414C/lw
 
-go 1.13
+go 1.21
+
+require github.com/go-logr/logr v1.4.4
@@ -0,0 +1,83 @@
+package lw
+
+import "sync/atomic"
+
+// Hook observes and optionally modifies Records before they are written.
+// Run returns the Record to be written and whether it should be written
+// at all.  Vetoing a Fatal Record prevents it from being written, but the
+// application is still terminated.  Hooks are run in the goroutine of the
+// logging call and must be safe for concurrent use.
+type Hook interface {
+	Run(r Record) (Record, bool)
+}
+
+// HookFunc is an adapter allowing an ordinary function to be used as a
+// Hook.
+type HookFunc func(r Record) (Record, bool)
+
+// Run calls f(r).
+func (f HookFunc) Run(r Record) (Record, bool) {
+	return f(r)
+}
+
+// hook is a Hook registered for Records at or above level min.
+type hook struct {
+	min Level
+	h   Hook
+}
+
+// hooks holds the registered hooks as an immutable []hook.
+var hooks atomic.Value
+
+func init() {
+	hooks.Store([]hook(nil))
+}
+
+// AddHook registers Hook h for all Records of message type min or above.
+// Hooks are run in the order they were registered, after redaction has
+// been applied.  AddHook may be called concurrently with logging.
+// Usage Example:
+// lw.AddHook(lw.LevelError, lw.HookFunc(alertOnError))
+func AddHook(min Level, h Hook) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	old := hooks.Load().([]hook)
+	hs := make([]hook, 0, len(old)+1)
+	hs = append(hs, old...)
+	hs = append(hs, hook{min: min, h: h})
+	hooks.Store(hs)
+}
+
+// ResetHooks removes all registered hooks.
+func ResetHooks() {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	hooks.Store([]hook(nil))
+}
+
+// runHooks runs the hooks registered for the message type of r, and
+// reports whether r should be written.  Each hook is passed a copy of r
+// holding its own Fields, as the Fields of r are reused once r has been
+// written.
+func runHooks(hs []hook, r *Record) bool {
+	for _, h := range hs {
+		if r.Level < h.min {
+			continue
+		}
+		nr, ok := h.h.Run(Record{
+			Level:   r.Level,
+			Time:    r.Time,
+			Logger:  r.Logger,
+			Message: r.Message,
+			Caller:  r.Caller,
+			Fields:  append([]Field(nil), r.Fields...),
+			detail:  r.detail,
+			stack:   r.stack,
+		})
+		if !ok {
+			return false
+		}
+		*r = nr
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package lw
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		InfoEnabled:    true,
+		WarningEnabled: true,
+		ErrorEnabled:   true,
+		TimeFormat:     TimeFormatNone,
+		RedactEnabled:  true,
+	}, &buf)
+	SetRedactFields("password")
+
+	var seen []Record
+	AddHook(LevelWarning, HookFunc(func(r Record) (Record, bool) {
+		seen = append(seen, r)
+		r.AddField("host", "web1")
+		return r, true
+	}))
+	AddHook(LevelInfo, HookFunc(func(r Record) (Record, bool) {
+		if strings.HasPrefix(r.Message, "drop") {
+			return r, false
+		}
+		r.Message = strings.ToUpper(r.Message)
+		return r, true
+	}))
+
+	Named("auth").Warningw("login failed", "user", 42, "password", "hunter2")
+	Info("info")
+	Errorf("drop me")
+	want := "WARNING:\t[auth] LOGIN FAILED user=42 password=[REDACTED] host=web1\n" +
+		"INFO:\tINFO\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	if len(seen) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(seen))
+	}
+	r := seen[0]
+	if r.Level != LevelWarning || r.Logger != "auth" || r.Message != "login failed" || r.Time.IsZero() {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if len(r.Fields) != 2 || r.Fields[0] != (Field{"user", 42}) || r.Fields[1] != (Field{"password", Redacted}) {
+		t.Errorf("unexpected fields %v", r.Fields)
+	}
+	if !strings.HasSuffix(r.Caller.File, "hooks_test.go") || r.Caller.Function != "github.com/1414C/lw.TestHooks" {
+		t.Errorf("unexpected caller %+v", r.Caller)
+	}
+
+	ResetHooks()
+	buf.Reset()
+	Errorf("drop me")
+	if want := "ERROR:\tdrop me\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestHooksConcurrent(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	Enable(false, false, &buf)
+	ErrorEnable(true)
+
+	var mu sync.Mutex
+	count := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			AddHook(LevelError, HookFunc(func(r Record) (Record, bool) {
+				mu.Lock()
+				count++
+				mu.Unlock()
+				return r, false
+			}))
+		}()
+		go func() {
+			defer wg.Done()
+			Error(errors.New("concurrent"))
+		}()
+	}
+	wg.Wait()
+
+	Error(errors.New("after"))
+	mu.Lock()
+	defer mu.Unlock()
+	if count == 0 {
+		t.Error("expected the hooks to run")
+	}
+}
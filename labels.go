@@ -0,0 +1,86 @@
+package lw
+
+import "strings"
+
+// LabelStyle determines the text of the label written at the start of each
+// log-entry to identify its message type.
+type LabelStyle int
+
+const (
+	// LabelFull writes the full upper-case name, as in "WARNING:" (default).
+	LabelFull LabelStyle = iota
+	// LabelShort writes a three letter abbreviation, as in "WRN:".
+	LabelShort
+	// LabelLower writes the full lower-case name, as in "warning:".
+	LabelLower
+	// LabelLetter writes the first letter of the name in the manner of
+	// glog, as in "W".
+	LabelLetter
+	// LabelNone omits the label.
+	LabelNone
+)
+
+// labelTexts holds the labels of each LabelStyle, indexed by Level.
+var labelTexts = [...][LevelOff]string{
+	LabelFull:   {"TRACE:", "DEBUG:", "INFO:", "WARNING:", "ERROR:", "FATAL:"},
+	LabelShort:  {"TRC:", "DBG:", "INF:", "WRN:", "ERR:", "FTL:"},
+	LabelLower:  {"trace:", "debug:", "info:", "warning:", "error:", "fatal:"},
+	LabelLetter: {"T", "D", "I", "W", "E", "F"},
+	LabelNone:   {},
+}
+
+// SetLabelStyle sets the style of the message type labels.
+// Usage Example:
+// lw.SetLabelStyle(lw.LabelShort)
+func SetLabelStyle(s LabelStyle) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.labelStyle = s
+	setLabels()
+}
+
+// LabelPadEnable enables/disables the padding of the message type labels.
+// When enabled, labels are padded with spaces to the width of the longest
+// label of their style, so that the following columns align regardless of
+// the tab width.  Otherwise labels are followed by a tab.
+func LabelPadEnable(a bool) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.labelPad = a
+	setLabels()
+}
+
+// setLabels sets the message type labels as per the current label style,
+// color settings and writer.  logWriter.mu must be held.
+func setLabels() {
+	color := useColor()
+	t := logWriter.theme
+	logWriter.lineColor = color && t.Line
+	styles := [LevelOff]Style{t.Trace, t.Debug, t.Info, t.Warning, t.Error, t.Fatal}
+	var txt [LevelOff]string
+	if logWriter.labelStyle >= LabelFull && logWriter.labelStyle < LabelNone {
+		txt = labelTexts[logWriter.labelStyle]
+	}
+	w := 0
+	for _, l := range txt {
+		if len(l) > w {
+			w = len(l)
+		}
+	}
+	for l := range txt {
+		if txt[l] == "" {
+			continue
+		}
+		if logWriter.labelPad {
+			txt[l] += strings.Repeat(" ", w-len(txt[l])+1)
+		} else {
+			txt[l] += "\t"
+		}
+	}
+	logWriter.traceTxt = paint(color, styles[LevelTrace], txt[LevelTrace])
+	logWriter.debugTxt = paint(color, styles[LevelDebug], txt[LevelDebug])
+	logWriter.infoTxt = paint(color, styles[LevelInfo], txt[LevelInfo])
+	logWriter.warnTxt = paint(color, styles[LevelWarning], txt[LevelWarning])
+	logWriter.errorTxt = paint(color, styles[LevelError], txt[LevelError])
+	logWriter.fatalTxt = paint(color, styles[LevelFatal], txt[LevelFatal])
+}
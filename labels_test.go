@@ -0,0 +1,64 @@
+package lw
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLabelStyle(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		InfoEnabled:    true,
+		WarningEnabled: true,
+		TimeFormat:     TimeFormatNone,
+	}, &buf)
+
+	tests := []struct {
+		style LabelStyle
+		pad   bool
+		want  string
+	}{
+		{LabelFull, false, "INFO:\tm\nWARNING:\tm\n"},
+		{LabelFull, true, "INFO:    m\nWARNING: m\n"},
+		{LabelShort, false, "INF:\tm\nWRN:\tm\n"},
+		{LabelShort, true, "INF: m\nWRN: m\n"},
+		{LabelLower, true, "info:    m\nwarning: m\n"},
+		{LabelLetter, false, "I\tm\nW\tm\n"},
+		{LabelNone, true, "m\nm\n"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		SetLabelStyle(tt.style)
+		LabelPadEnable(tt.pad)
+		Info("m")
+		Warning("m")
+		if buf.String() != tt.want {
+			t.Errorf("style %d, pad %v: got %q, want %q", tt.style, tt.pad, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestLabelColor(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:      true,
+		InfoEnabled:  true,
+		ColorEnabled: true,
+		ColorMode:    ColorAlways,
+		TimeFormat:   TimeFormatNone,
+		LabelStyle:   LabelNone,
+	}, &buf)
+
+	Info("none")
+	th := DefaultTheme()
+	th.Line = true
+	SetTheme(th)
+	Info("line")
+	want := "none\n\x1b[32;1mline\x1b[0m\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,99 @@
+package lw
+
+import "fmt"
+
+// Enabled reports whether log-entries of message type l are currently
+// being written by the package-level functions.  Use it to skip the
+// preparation of expensive log-entries.  Sampling and rate limits are not
+// taken into account.
+// Usage Example:
+// if lw.Enabled(lw.LevelDebug) { lw.Debugw("state", "dump", dumpState()) }
+func Enabled(l Level) bool {
+	return enabled(std, l)
+}
+
+// TraceEnabled reports whether Trace log-entries are currently being written.
+func TraceEnabled() bool {
+	return enabled(std, LevelTrace)
+}
+
+// DebugEnabled reports whether Debug log-entries are currently being written.
+func DebugEnabled() bool {
+	return enabled(std, LevelDebug)
+}
+
+// InfoEnabled reports whether Info log-entries are currently being written.
+func InfoEnabled() bool {
+	return enabled(std, LevelInfo)
+}
+
+// WarningEnabled reports whether Warning log-entries are currently being
+// written.
+func WarningEnabled() bool {
+	return enabled(std, LevelWarning)
+}
+
+// ErrorEnabled reports whether Error log-entries are currently being written.
+func ErrorEnabled() bool {
+	return enabled(std, LevelError)
+}
+
+// Enabled reports whether log-entries of message type lv are currently
+// being written by l, taking the level overrides applying to the name of l
+// into account.  See the package-level Enabled function.
+func (l *Logger) Enabled(lv Level) bool {
+	return enabled(l, lv)
+}
+
+// TraceEnabled reports whether Trace log-entries are currently being
+// written by l.
+func (l *Logger) TraceEnabled() bool {
+	return enabled(l, LevelTrace)
+}
+
+// DebugEnabled reports whether Debug log-entries are currently being
+// written by l.
+func (l *Logger) DebugEnabled() bool {
+	return enabled(l, LevelDebug)
+}
+
+// InfoEnabled reports whether Info log-entries are currently being
+// written by l.
+func (l *Logger) InfoEnabled() bool {
+	return enabled(l, LevelInfo)
+}
+
+// WarningEnabled reports whether Warning log-entries are currently being
+// written by l.
+func (l *Logger) WarningEnabled() bool {
+	return enabled(l, LevelWarning)
+}
+
+// ErrorEnabled reports whether Error log-entries are currently being
+// written by l.
+func (l *Logger) ErrorEnabled() bool {
+	return enabled(l, LevelError)
+}
+
+// Lazy is a value computed only when the log-entry using it is written.
+// It may be passed as an operand of the formatted logging functions or as
+// the value of a field, and is evaluated at most once per log-entry.  Any
+// fmt.Stringer is evaluated lazily in the same manner.
+// Usage Example:
+// lw.Debugf("state: %v", lw.Lazy(func() interface{} { return dumpState() }))
+// lw.Debugw("request", "body", lw.Lazy(func() interface{} { return string(body) }))
+type Lazy func() interface{}
+
+// String returns the rendered result of f.
+func (f Lazy) String() string {
+	return fmt.Sprint(f())
+}
+
+// resolveLazy replaces the Lazy values in fields f with their results.
+func resolveLazy(f []Field) {
+	for i := range f {
+		if lz, ok := f[i].Value.(Lazy); ok {
+			f[i].Value = lz()
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package lw
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{Enabled: true, InfoEnabled: true, ErrorEnabled: true}, &buf)
+
+	if !Enabled(LevelInfo) || !InfoEnabled() || !ErrorEnabled() {
+		t.Error("expected Info and Error to be enabled")
+	}
+	if Enabled(LevelDebug) || DebugEnabled() || TraceEnabled() || WarningEnabled() {
+		t.Error("expected Trace, Debug and Warning to be disabled")
+	}
+	if !Enabled(LevelFatal) {
+		t.Error("expected Fatal to be enabled")
+	}
+
+	if err := SetLevels("db=debug"); err != nil {
+		t.Fatal(err)
+	}
+	db := Named("db")
+	if !db.DebugEnabled() || !db.Enabled(LevelDebug) || db.TraceEnabled() {
+		t.Error("expected the level override of db to apply")
+	}
+	if New().DebugEnabled() {
+		t.Error("expected Debug to be disabled for the unnamed Logger")
+	}
+
+	Disable()
+	if InfoEnabled() || db.InfoEnabled() {
+		t.Error("expected all message types to be disabled")
+	}
+}
+
+func TestLazy(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{Enabled: true, InfoEnabled: true, TimeFormat: TimeFormatNone}, &buf)
+
+	calls := 0
+	lz := Lazy(func() interface{} {
+		calls++
+		return "expensive"
+	})
+	Debugf("state: %v", lz)
+	Debugw("state", "dump", lz)
+	if calls != 0 {
+		t.Errorf("expected no evaluation for disabled log-entries, got %d", calls)
+	}
+
+	Infof("state: %v", lz)
+	Infow("state", "dump", lz)
+	With("dump", lz).Info("derived")
+	if calls != 3 {
+		t.Errorf("expected 3 evaluations, got %d", calls)
+	}
+	want := "INFO:\tstate: expensive\nINFO:\tstate dump=expensive\nINFO:\tderived dump=expensive\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,220 @@
+package lw
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Level identifies the message type of a log-entry.  Levels are ordered
+// by severity, so that a level override enables all message types at or
+// above the given level.
+type Level int
+
+// Supported message types.  LevelOff is only meaningful as a level
+// override, where it disables all message types except Fatal.
+const (
+	LevelTrace Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarning
+	LevelError
+	LevelFatal
+	LevelOff
+)
+
+// LevelsEnv is the name of the environment variable read by
+// SetLevelsFromEnv.
+const LevelsEnv = "LW_LEVELS"
+
+var levelNames = []string{"trace", "debug", "info", "warning", "error", "fatal", "off"}
+
+// String returns the lower-case name of level l.
+func (l Level) String() string {
+	if l < LevelTrace || l > LevelOff {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the Level named by s.  Matching is case-insensitive
+// and "warn" is accepted as an alias of "warning".
+func ParseLevel(s string) (Level, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "warn" {
+		return LevelWarning, nil
+	}
+	for i, n := range levelNames {
+		if s == n {
+			return Level(i), nil
+		}
+	}
+	return LevelOff, fmt.Errorf("lw: unknown level %q", s)
+}
+
+// levelRule overrides the level of the Loggers whose names match pattern.
+type levelRule struct {
+	pattern string
+	level   Level
+}
+
+// levelRules is an immutable set of level overrides.  The level resolved
+// for each Logger name is cached.
+type levelRules struct {
+	spec   string
+	rules  []levelRule
+	def    Level
+	hasDef bool
+	cache  sync.Map
+}
+
+// resolvedLevel is the cached result of a level override lookup.
+type resolvedLevel struct {
+	level Level
+	ok    bool
+}
+
+// SetLevels overrides the levels of named Loggers as per spec, a
+// comma-separated list of pattern=level pairs.  Patterns are Logger names
+// and may contain the wildcards understood by path.Match.  When more than
+// one pattern matches, the longest pattern wins.  The pattern "default"
+// applies to all Loggers not matched by another pattern, including the
+// package-level functions.  Loggers without an override continue to use
+// the per message-type activations.  An empty spec removes all overrides.
+// Usage Example:
+// err := lw.SetLevels("db=debug,http.*=warning,default=info")
+func SetLevels(spec string) error {
+	r, err := parseLevels(spec)
+	if err != nil {
+		return err
+	}
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.levels.Store(r)
+	return nil
+}
+
+// SetLevelsFromEnv applies the level overrides held in the LW_LEVELS
+// environment variable.  See SetLevels for the format.  If the variable is
+// not set the current overrides are left intact.
+func SetLevelsFromEnv() error {
+	spec, ok := os.LookupEnv(LevelsEnv)
+	if !ok {
+		return nil
+	}
+	return SetLevels(spec)
+}
+
+// Levels returns the current level overrides in the format accepted by
+// SetLevels.
+func Levels() string {
+	if r, ok := logWriter.levels.Load().(*levelRules); ok && r != nil {
+		return r.spec
+	}
+	return ""
+}
+
+// LevelHandler returns an http.Handler for the administration of level
+// overrides.  A GET request returns the current overrides, while a PUT or
+// POST request replaces them with the overrides held in the request body.
+// The handler changes the logging of the whole process and performs no
+// authentication of its own, so it must only be mounted behind access
+// control, such as on an internal admin listener or behind an
+// authenticating middleware.
+// Usage Example:
+// http.Handle("/admin/lw/levels", lw.LevelHandler())
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			b, err := ioutil.ReadAll(io.LimitReader(r.Body, 64<<10))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := SetLevels(string(b)); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		io.WriteString(w, Levels()+"\n")
+	})
+}
+
+// parseLevels parses a level override spec.  nil is returned for an empty
+// spec.
+func parseLevels(spec string) (*levelRules, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+	r := &levelRules{}
+	var parts []string
+	for _, p := range strings.Split(spec, ",") {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("lw: invalid level override %q", p)
+		}
+		pattern := strings.TrimSpace(kv[0])
+		l, err := ParseLevel(kv[1])
+		if err != nil {
+			return nil, err
+		}
+		if pattern == "default" {
+			r.def, r.hasDef = l, true
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, fmt.Errorf("lw: invalid level override pattern %q", pattern)
+		}
+		r.rules = append(r.rules, levelRule{pattern: pattern, level: l})
+		parts = append(parts, pattern+"="+l.String())
+	}
+	if r.hasDef {
+		parts = append(parts, "default="+r.def.String())
+	}
+	r.spec = strings.Join(parts, ",")
+	sort.SliceStable(r.rules, func(i, j int) bool {
+		return len(r.rules[i].pattern) > len(r.rules[j].pattern)
+	})
+	return r, nil
+}
+
+// levelOverride returns the level override applying to the Logger named
+// name, if any.
+func levelOverride(name string) (Level, bool) {
+	r, ok := logWriter.levels.Load().(*levelRules)
+	if !ok || r == nil {
+		return 0, false
+	}
+	if v, ok := r.cache.Load(name); ok {
+		rl := v.(resolvedLevel)
+		return rl.level, rl.ok
+	}
+	rl := resolvedLevel{level: r.def, ok: r.hasDef}
+	if name != "" {
+		for _, rule := range r.rules {
+			if m, _ := path.Match(rule.pattern, name); m {
+				rl = resolvedLevel{level: rule.level, ok: true}
+				break
+			}
+		}
+	}
+	r.cache.Store(name, rl)
+	return rl.level, rl.ok
+}
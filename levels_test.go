@@ -0,0 +1,127 @@
+package lw
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for i, n := range []string{"trace", "DEBUG", "Info", "warn", "warning", "error", "fatal", "off"} {
+		want := Level(i)
+		if i >= 4 {
+			want = Level(i - 1)
+		}
+		l, err := ParseLevel(n)
+		if err != nil || l != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", n, l, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestSetLevels(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	Enable(false, false, &buf)
+	SetTimeFormat(TimeFormatNone)
+	InfoEnable(true)
+
+	if err := SetLevels("db=debug,http.*=warning,http.client=trace,default=error"); err != nil {
+		t.Fatal(err)
+	}
+	Named("db").Debug("db debug")
+	Named("db").Trace("db trace")
+	Named("http").Named("server").Info("server info")
+	Named("http").Named("server").Warning("server warning")
+	Named("http.client").Trace("client trace")
+	Named("cache").Warning("cache warning")
+	Named("cache").Errorf("cache error")
+	Info("package info")
+	want := "DEBUG:\t[db] db debug\n" +
+		"WARNING:\t[http.server] server warning\n" +
+		"TRACE:\t[http.client] client trace\n" +
+		"ERROR:\t[cache] cache error\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if s := GetState().Levels; s != "db=debug,http.*=warning,http.client=trace,default=error" {
+		t.Errorf("unexpected state %q", s)
+	}
+
+	// without a default, unmatched Loggers use the per message-type activations
+	buf.Reset()
+	if err := SetLevels("db=off"); err != nil {
+		t.Fatal(err)
+	}
+	Named("db").Error(nil)
+	Named("cache").Info("cache info")
+	Info("package info")
+	if want := "INFO:\t[cache] cache info\nINFO:\tpackage info\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	for _, spec := range []string{"db", "db=loud", "[=info"} {
+		if err := SetLevels(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+	if Levels() != "db=off" {
+		t.Errorf("invalid spec replaced overrides: %q", Levels())
+	}
+
+	InitWithSettings(LogWriterState{Enabled: true, Levels: "db=lod"}, &buf)
+	if Levels() != "db=off" {
+		t.Errorf("invalid spec in InitWithSettings replaced overrides: %q", Levels())
+	}
+	InitWithSettings(LogWriterState{Enabled: true}, &buf)
+	if Levels() != "" {
+		t.Errorf("expected an empty spec to remove the overrides, got %q", Levels())
+	}
+}
+
+func TestSetLevelsFromEnv(t *testing.T) {
+	defer DisableAndReset()
+	defer os.Unsetenv(LevelsEnv)
+	os.Setenv(LevelsEnv, "db=info")
+	if err := SetLevelsFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if Levels() != "db=info" {
+		t.Errorf("got %q", Levels())
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer DisableAndReset()
+	h := LevelHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader("db=debug,default=warn")))
+	if rec.Code != http.StatusOK || rec.Body.String() != "db=debug,default=warning\n" {
+		t.Errorf("PUT: got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("db=loud")))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("POST: got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "db=debug,default=warning\n" {
+		t.Errorf("GET: got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE: got %d", rec.Code)
+	}
+}
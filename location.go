@@ -0,0 +1,163 @@
+package lw
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// LocMode determines how the file portion of a caller location is rendered.
+type LocMode int
+
+const (
+	// LocFull renders the full path of the calling file (default).
+	LocFull LocMode = iota
+	// LocShort renders only the base name of the calling file.
+	LocShort
+	// LocRelative renders the path of the calling file relative to the
+	// configured LocRoot.  If LocRoot is empty, the module root is used,
+	// being the nearest directory holding a go.mod file at or above the
+	// working directory at the time LocRelative is selected, or else the
+	// working directory itself.  Files outside of the root are rendered
+	// with their full path.
+	LocRelative
+)
+
+// LocEnable enables/disables the reporting of the caller location for
+// all message types.
+func LocEnable(a bool) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.locEnabled = a
+}
+
+// SetLocMode sets the rendering of the caller location.  The location is
+// only written when lw has been enabled with location reporting.
+func SetLocMode(m LocMode) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.locMode = m
+	setLocBase()
+}
+
+// SetLocRoot sets the directory that file paths are trimmed against when
+// the LocRelative mode is in effect.  An empty root selects the module
+// root.
+func SetLocRoot(r string) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.locRoot = r
+	setLocBase()
+}
+
+// setLocBase determines the directory file paths are trimmed against in
+// LocRelative mode, so that it is not looked up for each log-entry.
+func setLocBase() {
+	switch {
+	case logWriter.locMode != LocRelative:
+		logWriter.locBase = ""
+	case logWriter.locRoot != "":
+		logWriter.locBase = logWriter.locRoot
+	default:
+		logWriter.locBase = moduleRoot()
+	}
+}
+
+// moduleRoot returns the nearest directory holding a go.mod file at or
+// above the working directory, or the working directory if there is none.
+func moduleRoot() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for d := wd; ; {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			return d
+		}
+		p := filepath.Dir(d)
+		if p == d {
+			return wd
+		}
+		d = p
+	}
+}
+
+// LocFuncEnable adds/removes the name of the calling function to/from the
+// caller location.
+func LocFuncEnable(a bool) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.locFunc = a
+}
+
+// SetCallerSkip sets the number of additional stack frames to skip when
+// determining the caller location.  Libraries that wrap lw in their own
+// logging functions can use this to report the location of their callers.
+func SetCallerSkip(n int) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.callerSkip = n
+}
+
+// callerOf returns the caller depth frames up, where 1 identifies the
+// caller of callerOf.  The skip configured via SetCallerSkip is added to
+// depth.
+func callerOf(depth int) Caller {
+	var pcs [1]uintptr
+	if runtime.Callers(depth+1+logWriter.callerSkip, pcs[:]) == 0 {
+		return Caller{}
+	}
+	// pcs[0] is a return address, so the call instruction precedes it.
+	fn := runtime.FuncForPC(pcs[0] - 1)
+	if fn == nil {
+		return Caller{}
+	}
+	f, line := fn.FileLine(pcs[0] - 1)
+	return Caller{File: f, Line: line, Function: fn.Name(), pc: pcs[0]}
+}
+
+// appendLocation appends the rendered caller location c, including the
+// leading separator, to b.
+func appendLocation(b []byte, c Caller) []byte {
+	if !logWriter.locEnabled || c.File == "" {
+		return b
+	}
+	b = append(b, '\t')
+	b = append(b, trimPath(c.File)...)
+	b = append(b, " line:"...)
+	b = strconv.AppendInt(b, int64(c.Line), 10)
+	if logWriter.locFunc && c.Function != "" {
+		b = append(b, " func:"...)
+		b = append(b, funcName(c.Function)...)
+	}
+	return b
+}
+
+// trimPath renders file f according to the current LocMode.
+func trimPath(f string) string {
+	switch logWriter.locMode {
+	case LocShort:
+		return filepath.Base(f)
+	case LocRelative:
+		if logWriter.locBase == "" {
+			return f
+		}
+		rel, err := filepath.Rel(logWriter.locBase, filepath.FromSlash(f))
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return f
+		}
+		return filepath.ToSlash(rel)
+	}
+	return f
+}
+
+// funcName strips the import path from a fully-qualified function name,
+// leaving the package name, (receiver) and function.
+func funcName(n string) string {
+	if i := strings.LastIndex(n, "/"); i >= 0 {
+		return n[i+1:]
+	}
+	return n
+}
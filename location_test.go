@@ -0,0 +1,142 @@
+package lw
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// nextLine returns the path of the calling file and the line following
+// the call, which is where the log call under test is placed.
+func nextLine() (string, int) {
+	_, f, line, _ := runtime.Caller(1)
+	return f, line + 1
+}
+
+func TestLocationAllLevels(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		LocEnabled:     true,
+		InfoEnabled:    true,
+		TraceEnabled:   true,
+		WarningEnabled: true,
+		DebugEnabled:   true,
+		ErrorEnabled:   true,
+		TimeFormat:     TimeFormatNone,
+		LocMode:        LocShort,
+	}, &buf)
+
+	lines := make([]int, 0, 6)
+	_, line := nextLine()
+	Info("msg")
+	lines = append(lines, line)
+	_, line = nextLine()
+	Trace("msg")
+	lines = append(lines, line)
+	_, line = nextLine()
+	Warning("msg")
+	lines = append(lines, line)
+	_, line = nextLine()
+	Debug("msg")
+	lines = append(lines, line)
+	_, line = nextLine()
+	Error(errors.New("msg"))
+	lines = append(lines, line)
+	_, line = nextLine()
+	ErrorWithPrefixString("pfx", errors.New("msg"))
+	lines = append(lines, line)
+
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(got) != len(lines) {
+		t.Fatalf("got %d lines, want %d: %q", len(got), len(lines), buf.String())
+	}
+	for i, l := range got {
+		if want := "msg\tlocation_test.go line:" + strconv.Itoa(lines[i]); !strings.HasSuffix(l, want) {
+			t.Errorf("got %q, want suffix %q", l, want)
+		}
+	}
+
+	// disabling the location must be honored by every level
+	buf.Reset()
+	LocEnable(false)
+	Info("msg")
+	Trace("msg")
+	Warning("msg")
+	Debug("msg")
+	Error(errors.New("msg"))
+	ErrorWithPrefixString("pfx", errors.New("msg"))
+	if strings.Contains(buf.String(), "line:") {
+		t.Errorf("unexpected location in %q", buf.String())
+	}
+}
+
+func TestLocModes(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	Enable(true, false, &buf)
+	InfoEnable(true)
+	SetTimeFormat(TimeFormatNone)
+
+	wd, _ := os.Getwd()
+	f, line := nextLine()
+	Info("full")
+	if want := "INFO:\tfull\t" + f + " line:" + strconv.Itoa(line) + "\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	SetLocMode(LocRelative)
+	SetLocRoot(filepath.Dir(wd))
+	_, line = nextLine()
+	Info("relative")
+	if want := "INFO:\trelative\t" + filepath.Base(wd) + "/location_test.go line:" + strconv.Itoa(line) + "\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// The module root is determined once, from the working directory at the
+	// time the root is set.
+	buf.Reset()
+	if err := os.Chdir("testdata"); err != nil {
+		t.Fatal(err)
+	}
+	SetLocRoot("")
+	os.Chdir("/")
+	_, line = nextLine()
+	Info("module root")
+	os.Chdir(wd)
+	if want := "INFO:\tmodule root\tlocation_test.go line:" + strconv.Itoa(line) + "\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	SetLocMode(LocShort)
+	LocFuncEnable(true)
+	_, line = nextLine()
+	Info("short")
+	if want := "INFO:\tshort\tlocation_test.go line:" + strconv.Itoa(line) + " func:lw.TestLocModes\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSetCallerSkip(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	Enable(true, false, &buf)
+	WarningEnable(true)
+	SetLocMode(LocShort)
+	SetCallerSkip(1)
+
+	wrapper := func() { Warning("msg") }
+	_, line := nextLine()
+	wrapper()
+	if want := "location_test.go line:" + strconv.Itoa(line) + "\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("got %q, want suffix %q", buf.String(), want)
+	}
+}
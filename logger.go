@@ -0,0 +1,221 @@
+package lw
+
+import "strings"
+
+// Logger is a handle on the package-level lw configuration.  A Logger
+// writes through the same writer and honors the same activation settings
+// as the package-level functions, but carries its own derivation state:
+// a dotted name and a list of fields written with each log-entry, an
+// optional rate limit, and the number of stack frames to skip when
+// reporting the caller location.
+// Loggers are immutable; the With* and Named methods return derived copies.
+// The zero value is ready to use.
+type Logger struct {
+	skip    int
+	name    string
+	fields  []interface{}
+	limiter *rateLimiter
+}
+
+// std is the Logger used by the package-level functions.
+var std = &Logger{}
+
+// New returns a Logger that behaves like the package-level functions.
+func New() *Logger {
+	return &Logger{}
+}
+
+// WithCallerSkip returns a Logger that skips n additional stack frames
+// when reporting the caller location.  This allows helpers wrapping lw to
+// attribute log-entries to their own callers.
+// Usage Example:
+// var dbLog = lw.WithCallerSkip(1)
+// func logDBError(e error) { dbLog.Error(e) }
+func WithCallerSkip(n int) *Logger {
+	return std.WithCallerSkip(n)
+}
+
+// With returns a Logger that writes the alternating keys and values in kv
+// after the message of each log-entry.
+// Usage Example:
+// authLog := lw.With("component", "auth")
+func With(kv ...interface{}) *Logger {
+	return std.With(kv...)
+}
+
+// Named returns a Logger that writes name in brackets ahead of the
+// message of each log-entry.
+// Usage Example:
+// dbLog := lw.Named("db")
+func Named(name string) *Logger {
+	return std.Named(name)
+}
+
+// WithCallerSkip returns a copy of l that skips n stack frames in addition
+// to those already skipped by l.  This supports wrappers of wrappers.
+func (l *Logger) WithCallerSkip(n int) *Logger {
+	c := *l
+	c.skip += n
+	return &c
+}
+
+// With returns a copy of l that writes the alternating keys and values in
+// kv after the fields already written by l.
+func (l *Logger) With(kv ...interface{}) *Logger {
+	c := *l
+	c.fields = make([]interface{}, 0, len(l.fields)+len(kv))
+	c.fields = append(c.fields, l.fields...)
+	c.fields = append(c.fields, kv...)
+	return &c
+}
+
+// Named returns a copy of l whose name is the name of l and name joined
+// by a dot.
+// Usage Example:
+// lw.Named("api").Named("auth").Info("login ok") // INFO: ... [api.auth] login ok
+func (l *Logger) Named(name string) *Logger {
+	c := *l
+	c.name = strings.Trim(l.name+"."+name, ".")
+	return &c
+}
+
+// Info writes an Info message.  See the package-level Info function.
+func (l *Logger) Info(i ...interface{}) {
+	logp(2, l, LevelInfo, i...)
+}
+
+// Infof writes a formatted Info message.  See the package-level Infof function.
+func (l *Logger) Infof(s string, i ...interface{}) {
+	logf(2, l, LevelInfo, s, i...)
+}
+
+// InfoE writes an Info message carrying an error.  See the package-level InfoE
+// function.
+func (l *Logger) InfoE(e error, s string, i ...interface{}) {
+	loge(2, l, LevelInfo, e, s, i...)
+}
+
+// Infow writes an Info message with key/value pairs.  See the package-level
+// Infow function.
+func (l *Logger) Infow(s string, kv ...interface{}) {
+	logw(2, l, LevelInfo, s, kv...)
+}
+
+// Trace writes a Trace message.  See the package-level Trace function.
+func (l *Logger) Trace(i ...interface{}) {
+	logp(2, l, LevelTrace, i...)
+}
+
+// Tracef writes a formatted Trace message.  See the package-level Tracef function.
+func (l *Logger) Tracef(s string, i ...interface{}) {
+	logf(2, l, LevelTrace, s, i...)
+}
+
+// TraceE writes a Trace message carrying an error.  See the package-level TraceE
+// function.
+func (l *Logger) TraceE(e error, s string, i ...interface{}) {
+	loge(2, l, LevelTrace, e, s, i...)
+}
+
+// Tracew writes a Trace message with key/value pairs.  See the package-level
+// Tracew function.
+func (l *Logger) Tracew(s string, kv ...interface{}) {
+	logw(2, l, LevelTrace, s, kv...)
+}
+
+// Warning writes a Warning message.  See the package-level Warning function.
+func (l *Logger) Warning(i ...interface{}) {
+	logp(2, l, LevelWarning, i...)
+}
+
+// Warningf writes a formatted Warning message.  See the package-level Warningf function.
+func (l *Logger) Warningf(s string, i ...interface{}) {
+	logf(2, l, LevelWarning, s, i...)
+}
+
+// WarningE writes a Warning message carrying an error.  See the package-level WarningE
+// function.
+func (l *Logger) WarningE(e error, s string, i ...interface{}) {
+	loge(2, l, LevelWarning, e, s, i...)
+}
+
+// Warningw writes a Warning message with key/value pairs.  See the package-level
+// Warningw function.
+func (l *Logger) Warningw(s string, kv ...interface{}) {
+	logw(2, l, LevelWarning, s, kv...)
+}
+
+// Debug writes a Debug message.  See the package-level Debug function.
+func (l *Logger) Debug(i ...interface{}) {
+	logp(2, l, LevelDebug, i...)
+}
+
+// Debugf writes a formatted Debug message.  See the package-level Debugf function.
+func (l *Logger) Debugf(s string, i ...interface{}) {
+	logf(2, l, LevelDebug, s, i...)
+}
+
+// DebugE writes a Debug message carrying an error.  See the package-level DebugE
+// function.
+func (l *Logger) DebugE(e error, s string, i ...interface{}) {
+	loge(2, l, LevelDebug, e, s, i...)
+}
+
+// Debugw writes a Debug message with key/value pairs.  See the package-level
+// Debugw function.
+func (l *Logger) Debugw(s string, kv ...interface{}) {
+	logw(2, l, LevelDebug, s, kv...)
+}
+
+// Error writes an Error message.  See the package-level Error function.
+func (l *Logger) Error(e error) {
+	logerr(2, l, LevelError, "", e)
+}
+
+// Errorf writes a formatted Error message.  See the package-level Errorf
+// function.
+func (l *Logger) Errorf(s string, i ...interface{}) {
+	logf(2, l, LevelError, s, i...)
+}
+
+// ErrorE writes an Error message carrying an error.  See the package-level
+// ErrorE function.
+func (l *Logger) ErrorE(e error, s string, i ...interface{}) {
+	loge(2, l, LevelError, e, s, i...)
+}
+
+// Errorw writes an Error message with key/value pairs.  See the
+// package-level Errorw function.
+func (l *Logger) Errorw(s string, kv ...interface{}) {
+	logw(2, l, LevelError, s, kv...)
+}
+
+// ErrorWithPrefixString writes a prefixed Error message.  See the
+// package-level ErrorWithPrefixString function.
+func (l *Logger) ErrorWithPrefixString(s string, e error) {
+	logerr(2, l, LevelError, s, e)
+}
+
+// Fatal writes a Fatal log-entry and terminates the application.  See the
+// package-level Fatal function.
+func (l *Logger) Fatal(e error) {
+	logerr(2, l, LevelFatal, "", e)
+}
+
+// Fatalf writes a formatted Fatal log-entry and terminates the application.
+// See the package-level Fatalf function.
+func (l *Logger) Fatalf(s string, i ...interface{}) {
+	logf(2, l, LevelFatal, s, i...)
+}
+
+// FatalE writes a Fatal log-entry carrying an error and terminates the
+// application.  See the package-level FatalE function.
+func (l *Logger) FatalE(e error, s string, i ...interface{}) {
+	loge(2, l, LevelFatal, e, s, i...)
+}
+
+// Fatalw writes a Fatal log-entry with key/value pairs and terminates the
+// application.  See the package-level Fatalw function.
+func (l *Logger) Fatalw(s string, kv ...interface{}) {
+	logw(2, l, LevelFatal, s, kv...)
+}
@@ -0,0 +1,93 @@
+package lw
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+var (
+	helperLog = WithCallerSkip(1)
+	nestedLog = helperLog.WithCallerSkip(1)
+)
+
+func logDBError(e error) {
+	helperLog.Error(e)
+}
+
+func logDBWarning(s string) {
+	nestedLog.Warning(s)
+}
+
+func wrapDBWarning(s string) {
+	logDBWarning(s)
+}
+
+func TestWithCallerSkip(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		LocEnabled:     true,
+		WarningEnabled: true,
+		ErrorEnabled:   true,
+		TimeFormat:     TimeFormatNone,
+		LocMode:        LocShort,
+	}, &buf)
+
+	_, line := nextLine()
+	logDBError(errors.New("wrapped"))
+	if want := "ERROR:\twrapped\tlogger_test.go line:" + strconv.Itoa(line) + "\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	_, line = nextLine()
+	wrapDBWarning("nested")
+	if want := "WARNING:\tnested\tlogger_test.go line:" + strconv.Itoa(line) + "\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	l := New()
+	_, line = nextLine()
+	l.Warning("direct")
+	if want := "WARNING:\tdirect\tlogger_test.go line:" + strconv.Itoa(line) + "\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWithNamed(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:      true,
+		InfoEnabled:  true,
+		ErrorEnabled: true,
+		TimeFormat:   TimeFormatNone,
+	}, &buf)
+
+	api := Named("api").With("version", 2)
+	auth := api.Named("auth").With("user", 42)
+
+	auth.Info("login ok")
+	auth.Infow("token issued", "ttl", "1h")
+	auth.ErrorE(errors.New("expired"), "refresh failed")
+	api.Info("parent unchanged")
+	want := "INFO:\t[api.auth] login ok version=2 user=42\n" +
+		"INFO:\t[api.auth] token issued version=2 user=42 ttl=1h\n" +
+		"ERROR:\t[api.auth] refresh failed: expired version=2 user=42\n" +
+		"INFO:\t[api] parent unchanged version=2\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// children share the level configuration of the package
+	buf.Reset()
+	InfoEnable(false)
+	auth.Info("disabled")
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
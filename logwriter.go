@@ -4,9 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"runtime"
-	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -28,6 +27,30 @@ type LogWriter struct {
 	traceTxt       string
 	debugTxt       string
 	fatalTxt       string
+	timeFormat     string
+	utc            bool
+	clock          func() time.Time
+	locMode        LocMode
+	locRoot        string
+	locBase        string
+	locFunc        bool
+	callerSkip     int
+	stackEnabled   bool
+	stackDepth     int
+	stackTrim      bool
+	errorDetail    bool
+	levels         atomic.Value
+	sampleFirst    int
+	sampleEvery    int
+	sampleInterval time.Duration
+	dedupWindow    time.Duration
+	redactEnabled  bool
+	colorMode      ColorMode
+	theme          Theme
+	lineColor      bool
+	labelStyle     LabelStyle
+	labelPad       bool
+	multiLine      MultiLineMode
 }
 
 // LogWriterState is used to return the current status/state
@@ -41,26 +64,36 @@ type LogWriterState struct {
 	DebugEnabled   bool
 	ErrorEnabled   bool
 	ColorEnabled   bool
+	TimeFormat     string
+	UTC            bool
+	Clock          func() time.Time
+	LocMode        LocMode
+	LocRoot        string
+	LocFunc        bool
+	CallerSkip     int
+	StackEnabled   bool
+	StackDepth     int
+	StackTrim      bool
+	ErrorDetail    bool
+	Levels         string
+	SampleFirst    int
+	SampleEvery    int
+	SampleInterval time.Duration
+	DedupWindow    time.Duration
+	RedactEnabled  bool
+	ColorMode      ColorMode
+	Theme          *Theme
+	LabelStyle     LabelStyle
+	LabelPad       bool
+	MultiLine      MultiLineMode
 }
 
 var logWriter LogWriter
 
-func withColor(b bool) {
-	if b {
-		logWriter.infoTxt = "\x1b[32;1mINFO:\t\x1b[0m"
-		logWriter.warnTxt = "\x1b[38;5;11mWARNING:  \x1b[0m"
-		logWriter.errorTxt = "\x1b[38;5;9mERROR:\t\x1b[0m"
-		logWriter.traceTxt = "\x1b[38;5;13mTRACE:\t\x1b[0m"
-		logWriter.debugTxt = "\x1b[38;5;213mDEBUG:\t\x1b[0m"
-		logWriter.fatalTxt = "\x1b[38;5;9mFATAL:\t\x1b[0m"
-		return
-	}
-	logWriter.infoTxt = "INFO:\t"
-	logWriter.warnTxt = "WARNING:  "
-	logWriter.errorTxt = "ERROR:\t"
-	logWriter.traceTxt = "TRACE:\t"
-	logWriter.debugTxt = "DEBUG:\t"
-	logWriter.fatalTxt = "FATAL:\t"
+func init() {
+	logWriter.writer = os.Stdout
+	logWriter.theme = DefaultTheme()
+	setLabels()
 }
 
 // Enable enables lw at the package-level.  This does not have the
@@ -75,15 +108,16 @@ func Enable(withLoc bool, withCol bool, w io.Writer) {
 	logWriter.enabled = true
 	logWriter.locEnabled = withLoc
 	logWriter.colorEnabled = withCol
-	withColor(logWriter.colorEnabled)
+	logWriter.writer = os.Stdout
 	if w != nil {
 		logWriter.writer = w
-		return
 	}
-	logWriter.writer = os.Stdout
+	setLabels()
 }
 
-// InitWithSettings configures lw as per the supplied parameters.
+// InitWithSettings configures lw as per the supplied parameters.  An
+// invalid Levels spec is ignored and the current level overrides are
+// kept; use SetLevels to validate a spec and obtain the error.
 func InitWithSettings(s LogWriterState, w io.Writer) {
 	logWriter.mu.Lock()
 	defer logWriter.mu.Unlock()
@@ -95,12 +129,41 @@ func InitWithSettings(s LogWriterState, w io.Writer) {
 	logWriter.debugEnabled = s.DebugEnabled
 	logWriter.errorEnabled = s.ErrorEnabled
 	logWriter.colorEnabled = s.ColorEnabled
-	withColor(logWriter.colorEnabled)
+	logWriter.timeFormat = s.TimeFormat
+	logWriter.utc = s.UTC
+	logWriter.clock = s.Clock
+	logWriter.locMode = s.LocMode
+	logWriter.locRoot = s.LocRoot
+	setLocBase()
+	logWriter.locFunc = s.LocFunc
+	logWriter.callerSkip = s.CallerSkip
+	logWriter.stackEnabled = s.StackEnabled
+	logWriter.stackDepth = s.StackDepth
+	logWriter.stackTrim = s.StackTrim
+	logWriter.errorDetail = s.ErrorDetail
+	if r, err := parseLevels(s.Levels); err == nil {
+		logWriter.levels.Store(r)
+	}
+	logWriter.sampleFirst = s.SampleFirst
+	logWriter.sampleEvery = s.SampleEvery
+	logWriter.sampleInterval = s.SampleInterval
+	samples.reset()
+	dedup.flush()
+	logWriter.dedupWindow = s.DedupWindow
+	logWriter.redactEnabled = s.RedactEnabled
+	logWriter.colorMode = s.ColorMode
+	logWriter.theme = DefaultTheme()
+	if s.Theme != nil {
+		logWriter.theme = *s.Theme
+	}
+	logWriter.labelStyle = s.LabelStyle
+	logWriter.labelPad = s.LabelPad
+	logWriter.multiLine = s.MultiLine
+	logWriter.writer = os.Stdout
 	if w != nil {
 		logWriter.writer = w
-		return
 	}
-	logWriter.writer = os.Stdout
+	setLabels()
 }
 
 // Disable disables lw at the package-level, but leaves all current
@@ -126,7 +189,35 @@ func DisableAndReset() {
 	logWriter.debugEnabled = false
 	logWriter.errorEnabled = false
 	logWriter.colorEnabled = false
-	withColor(logWriter.colorEnabled)
+	logWriter.timeFormat = ""
+	logWriter.utc = false
+	logWriter.clock = nil
+	logWriter.locMode = LocFull
+	logWriter.locRoot = ""
+	logWriter.locBase = ""
+	logWriter.locFunc = false
+	logWriter.callerSkip = 0
+	logWriter.stackEnabled = false
+	logWriter.stackDepth = 0
+	logWriter.stackTrim = false
+	logWriter.errorDetail = false
+	logWriter.levels.Store((*levelRules)(nil))
+	logWriter.sampleFirst = 0
+	logWriter.sampleEvery = 0
+	logWriter.sampleInterval = 0
+	samples.reset()
+	limiters.drain()
+	dedup.flush()
+	logWriter.dedupWindow = 0
+	logWriter.redactEnabled = false
+	resetRedaction()
+	hooks.Store([]hook(nil))
+	logWriter.colorMode = ColorAuto
+	logWriter.theme = DefaultTheme()
+	logWriter.labelStyle = LabelFull
+	logWriter.labelPad = false
+	logWriter.multiLine = MultiLineAsIs
+	setLabels()
 }
 
 // SetWriter uses the supplied writer to set the output of the
@@ -135,10 +226,11 @@ func DisableAndReset() {
 func SetWriter(w io.Writer) {
 	logWriter.mu.Lock()
 	defer logWriter.mu.Unlock()
+	logWriter.writer = os.Stdout
 	if w != nil {
 		logWriter.writer = w
 	}
-	logWriter.writer = os.Stdout
+	setLabels()
 }
 
 // GetState returns the current state of the lw settings.  Note
@@ -157,7 +249,30 @@ func GetState() LogWriterState {
 		DebugEnabled:   logWriter.debugEnabled,
 		ErrorEnabled:   logWriter.errorEnabled,
 		ColorEnabled:   logWriter.colorEnabled,
+		TimeFormat:     logWriter.timeFormat,
+		UTC:            logWriter.utc,
+		Clock:          logWriter.clock,
+		LocMode:        logWriter.locMode,
+		LocRoot:        logWriter.locRoot,
+		LocFunc:        logWriter.locFunc,
+		CallerSkip:     logWriter.callerSkip,
+		StackEnabled:   logWriter.stackEnabled,
+		StackDepth:     logWriter.stackDepth,
+		StackTrim:      logWriter.stackTrim,
+		ErrorDetail:    logWriter.errorDetail,
+		Levels:         Levels(),
+		SampleFirst:    logWriter.sampleFirst,
+		SampleEvery:    logWriter.sampleEvery,
+		SampleInterval: logWriter.sampleInterval,
+		DedupWindow:    logWriter.dedupWindow,
+		RedactEnabled:  logWriter.redactEnabled,
+		ColorMode:      logWriter.colorMode,
+		LabelStyle:     logWriter.labelStyle,
+		LabelPad:       logWriter.labelPad,
+		MultiLine:      logWriter.multiLine,
 	}
+	t := logWriter.theme
+	s.Theme = &t
 	return s
 }
 
@@ -206,12 +321,13 @@ func ErrorEnable(a bool) {
 	logWriter.errorEnabled = a
 }
 
-// ColorEnable sets/unsets the coloring of the message type.
+// ColorEnable sets/unsets the coloring of the message type.  By default
+// colors are only written if the writer is a terminal; see ColorMode.
 func ColorEnable(c bool) {
 	logWriter.mu.Lock()
 	defer logWriter.mu.Unlock()
 	logWriter.colorEnabled = c
-	withColor(c)
+	setLabels()
 }
 
 // Console always writes to os.Stdout regardless of the lw.Enabled setting.
@@ -222,92 +338,139 @@ func Console(s string, i ...interface{}) {
 }
 
 // Info writes an Info message based on the current lw settings.  The method accepts a
+// list of operands that are formatted in the manner of fmt.Sprint.
+// Note that you do not need to pass the newline escape code ("\n").
+// Usage Example:
+// lw.Info("This is a test MESSAGE with the number ", 42)
+func Info(i ...interface{}) {
+	logp(2, std, LevelInfo, i...)
+}
+
+// Infof writes an Info message based on the current lw settings.  The method accepts a
 // Printf-type formatted string and a list of operands to use in the verb-replacement.
 // Note that you do not need to pass the newline escape code ("\n").
 // Usage Example:
-// lw.Info("This is a test %s with the number %d", "MESSAGE", 42)
-func Info(s string, i ...interface{}) {
-	if !logWriter.enabled {
-		return
-	}
-	if logWriter.infoEnabled {
-		m := fmt.Sprintf(s, i...)
-		if logWriter.locEnabled {
-			_, f, line, ok := runtime.Caller(1)
-			if ok {
-				io.WriteString(logWriter.writer, logWriter.infoTxt+time.Now().Format(time.RFC3339Nano)+"\t"+m+"\t"+f+" line:"+strconv.Itoa(line)+"\n")
-				return
-			}
-			io.WriteString(logWriter.writer, logWriter.infoTxt+time.Now().Format(time.RFC3339Nano)+"\t"+m+"\n")
-			return
-		}
-		io.WriteString(logWriter.writer, logWriter.infoTxt+time.Now().Format(time.RFC3339Nano)+"\t"+m+"\n")
-	}
+// lw.Infof("This is a test %s with the number %d", "MESSAGE", 42)
+func Infof(s string, i ...interface{}) {
+	logf(2, std, LevelInfo, s, i...)
+}
+
+// InfoE writes an Info message carrying an error based on the current lw settings.  The
+// Printf-type formatted message is followed by the rendered error.
+// Usage Example:
+// lw.InfoE(e, "Could not load %s", "config.json")
+func InfoE(e error, s string, i ...interface{}) {
+	loge(2, std, LevelInfo, e, s, i...)
+}
+
+// Infow writes an Info message followed by a list of alternating keys and values based
+// on the current lw settings.
+// Usage Example:
+// lw.Infow("Login ok", "user", 42, "method", "password")
+func Infow(s string, kv ...interface{}) {
+	logw(2, std, LevelInfo, s, kv...)
 }
 
 // Trace writes a Trace message based on the current lw settings.  The method accepts a
+// list of operands that are formatted in the manner of fmt.Sprint.
+// Note that you do not need to pass the newline escape code ("\n").
+// Usage Example:
+// lw.Trace("This is a test MESSAGE with the number ", 42)
+func Trace(i ...interface{}) {
+	logp(2, std, LevelTrace, i...)
+}
+
+// Tracef writes a Trace message based on the current lw settings.  The method accepts a
 // Printf-type formatted string and a list of operands to use in the verb-replacement.
 // Note that you do not need to pass the newline escape code ("\n").
 // Usage Example:
-// lw.Trace("This is a test %s with the number %d", "MESSAGE", 42)
-func Trace(s string, i ...interface{}) {
-	if !logWriter.enabled {
-		return
-	}
-	if logWriter.traceEnabled {
-		m := fmt.Sprintf(s, i...)
-		_, f, line, ok := runtime.Caller(1)
-		if ok {
-			io.WriteString(logWriter.writer, logWriter.traceTxt+time.Now().Format(time.RFC3339Nano)+"\t"+m+"\t"+f+" line:"+strconv.Itoa(line)+"\n")
-			return
-		}
-		io.WriteString(logWriter.writer, logWriter.traceTxt+time.Now().Format(time.RFC3339Nano)+"\t"+m+"\n")
-	}
+// lw.Tracef("This is a test %s with the number %d", "MESSAGE", 42)
+func Tracef(s string, i ...interface{}) {
+	logf(2, std, LevelTrace, s, i...)
+}
+
+// TraceE writes a Trace message carrying an error based on the current lw settings.  The
+// Printf-type formatted message is followed by the rendered error.
+// Usage Example:
+// lw.TraceE(e, "Could not load %s", "config.json")
+func TraceE(e error, s string, i ...interface{}) {
+	loge(2, std, LevelTrace, e, s, i...)
+}
+
+// Tracew writes a Trace message followed by a list of alternating keys and values based
+// on the current lw settings.
+// Usage Example:
+// lw.Tracew("Login ok", "user", 42, "method", "password")
+func Tracew(s string, kv ...interface{}) {
+	logw(2, std, LevelTrace, s, kv...)
+}
+
+// Warning writes a Warning message based on the current lw settings.  The method accepts a
+// list of operands that are formatted in the manner of fmt.Sprint.
+// Note that you do not need to pass the newline escape code ("\n").
+// Usage Example:
+// lw.Warning("This is a test MESSAGE with the number ", 42)
+func Warning(i ...interface{}) {
+	logp(2, std, LevelWarning, i...)
 }
 
-// Warning writes a Warning mesage based on the current lw settings.  The method accepts a
+// Warningf writes a Warning message based on the current lw settings.  The method accepts a
 // Printf-type formatted string and a list of operands to use in the verb-replacement.
 // Note that you do not need to pass the newline escape code ("\n").
 // Usage Example:
-// lw.Warning("This is a test %s with the number %d", "MESSAGE", 42)
-func Warning(s string, i ...interface{}) {
-	if !logWriter.enabled {
-		return
-	}
-	if logWriter.warningEnabled {
-		m := fmt.Sprintf(s, i...)
-		if logWriter.locEnabled {
-			_, f, line, ok := runtime.Caller(1)
-			if ok {
-				io.WriteString(logWriter.writer, logWriter.warnTxt+time.Now().Format(time.RFC3339Nano)+"\t"+m+"\t"+f+" line:"+strconv.Itoa(line)+"\n")
-				return
-			}
-			io.WriteString(logWriter.writer, logWriter.warnTxt+time.Now().Format(time.RFC3339Nano)+"\t"+m+"\n")
-			return
-		}
-		io.WriteString(logWriter.writer, logWriter.warnTxt+time.Now().Format(time.RFC3339Nano)+"\t"+m+"\n")
-	}
+// lw.Warningf("This is a test %s with the number %d", "MESSAGE", 42)
+func Warningf(s string, i ...interface{}) {
+	logf(2, std, LevelWarning, s, i...)
+}
+
+// WarningE writes a Warning message carrying an error based on the current lw settings.  The
+// Printf-type formatted message is followed by the rendered error.
+// Usage Example:
+// lw.WarningE(e, "Could not load %s", "config.json")
+func WarningE(e error, s string, i ...interface{}) {
+	loge(2, std, LevelWarning, e, s, i...)
+}
+
+// Warningw writes a Warning message followed by a list of alternating keys and values based
+// on the current lw settings.
+// Usage Example:
+// lw.Warningw("Login ok", "user", 42, "method", "password")
+func Warningw(s string, kv ...interface{}) {
+	logw(2, std, LevelWarning, s, kv...)
 }
 
 // Debug writes a Debug message based on the current lw settings.  The method accepts a
+// list of operands that are formatted in the manner of fmt.Sprint.
+// Note that you do not need to pass the newline escape code ("\n").
+// Usage Example:
+// lw.Debug("This is a test MESSAGE with the number ", 42)
+func Debug(i ...interface{}) {
+	logp(2, std, LevelDebug, i...)
+}
+
+// Debugf writes a Debug message based on the current lw settings.  The method accepts a
 // Printf-type formatted string and a list of operands to use in the verb-replacement.
 // Note that you do not need to pass the newline escape code ("\n").
 // Usage Example:
-// lw.Debug("This is a test %s with the number %d", "MESSAGE", 42)
-func Debug(s string, i ...interface{}) {
-	if !logWriter.enabled {
-		return
-	}
-	if logWriter.debugEnabled {
-		m := fmt.Sprintf(s, i...)
-		_, f, line, ok := runtime.Caller(1)
-		if ok {
-			// io.WriteString(logWriter.writer, logWriter.debugTxt+time.Now().Format(time.RFC3339Nano)+"\t"+f+" line:"+strconv.Itoa(line)+"\t"+m+"\n")
-			io.WriteString(logWriter.writer, logWriter.debugTxt+time.Now().Format(time.RFC3339Nano)+"\t"+m+"\t"+f+" line:"+strconv.Itoa(line)+"\n")
-			return
-		}
-		io.WriteString(logWriter.writer, logWriter.debugTxt+time.Now().Format(time.RFC3339Nano)+"\t"+m+"\n")
-	}
+// lw.Debugf("This is a test %s with the number %d", "MESSAGE", 42)
+func Debugf(s string, i ...interface{}) {
+	logf(2, std, LevelDebug, s, i...)
+}
+
+// DebugE writes a Debug message carrying an error based on the current lw settings.  The
+// Printf-type formatted message is followed by the rendered error.
+// Usage Example:
+// lw.DebugE(e, "Could not load %s", "config.json")
+func DebugE(e error, s string, i ...interface{}) {
+	loge(2, std, LevelDebug, e, s, i...)
+}
+
+// Debugw writes a Debug message followed by a list of alternating keys and values based
+// on the current lw settings.
+// Usage Example:
+// lw.Debugw("Login ok", "user", 42, "method", "password")
+func Debugw(s string, kv ...interface{}) {
+	logw(2, std, LevelDebug, s, kv...)
 }
 
 // Error writes an Error message based on the current lw settings.  The method accepts
@@ -316,18 +479,32 @@ func Debug(s string, i ...interface{}) {
 // Usage Example:
 // lw.Error(e)
 func Error(e error) {
-	if !logWriter.enabled {
-		return
-	}
-	if logWriter.errorEnabled {
-		_, f, line, ok := runtime.Caller(1)
-		if ok {
-			// io.WriteString(logWriter.writer, logWriter.errorTxt+time.Now().Format(time.RFC3339Nano)+"\t"+f+" line:"+strconv.Itoa(line)+"\t"+e.Error()+"\n")
-			io.WriteString(logWriter.writer, logWriter.errorTxt+time.Now().Format(time.RFC3339Nano)+"\t"+e.Error()+"\t"+f+" line:"+strconv.Itoa(line)+"\n")
-			return
-		}
-		io.WriteString(logWriter.writer, logWriter.errorTxt+time.Now().Format(time.RFC3339Nano)+"\t"+e.Error()+"\n")
-	}
+	logerr(2, std, LevelError, "", e)
+}
+
+// Errorf writes an Error message based on the current lw settings.  The method accepts a
+// Printf-type formatted string and a list of operands to use in the verb-replacement.
+// Note that you do not need to pass the newline escape code ("\n").
+// Usage Example:
+// lw.Errorf("This is a test %s with the number %d", "MESSAGE", 42)
+func Errorf(s string, i ...interface{}) {
+	logf(2, std, LevelError, s, i...)
+}
+
+// ErrorE writes an Error message carrying an error based on the current lw settings.  The
+// Printf-type formatted message is followed by the rendered error.
+// Usage Example:
+// lw.ErrorE(e, "Auth Controller Create() failed for user %d", 42)
+func ErrorE(e error, s string, i ...interface{}) {
+	loge(2, std, LevelError, e, s, i...)
+}
+
+// Errorw writes an Error message followed by a list of alternating keys and values based
+// on the current lw settings.
+// Usage Example:
+// lw.Errorw("Login failed", "user", 42, "method", "password")
+func Errorw(s string, kv ...interface{}) {
+	logw(2, std, LevelError, s, kv...)
 }
 
 // ErrorWithPrefixString writes an Error message based on the current lw settings.  The
@@ -337,34 +514,42 @@ func Error(e error) {
 // e error
 // lw.ErrorWithPrefixString("Auth Controller Create() got:", e)
 func ErrorWithPrefixString(s string, e error) {
-	if !logWriter.enabled {
-		return
-	}
-	if logWriter.errorEnabled {
-		_, f, line, ok := runtime.Caller(1)
-		if ok {
-			// io.WriteString(logWriter.writer, time.Now().Format(time.RFC3339Nano)+"\t ERROR: "+s+" "+f+" line:"+strconv.Itoa(line)+" "+e.Error()+"\n")
-			io.WriteString(logWriter.writer, logWriter.errorTxt+time.Now().Format(time.RFC3339Nano)+"\t"+s+" "+f+" line:"+strconv.Itoa(line)+"\t"+e.Error()+"\n")
-			return
-		}
-		// io.WriteString(logWriter.writer, time.Now().Format(time.RFC3339Nano)+"\t ERROR: "+s+" "+e.Error()+"\n")
-		io.WriteString(logWriter.writer, logWriter.errorTxt+time.Now().Format(time.RFC3339Nano)+"\t"+s+" "+e.Error()+"\n")
-	}
+	logerr(2, std, LevelError, s, e)
 }
 
-// Fatal writes a Fatal log-entry based on the current lw settings and the terminates
+// Fatal writes a Fatal log-entry based on the current lw settings and then terminates
+// the application via os.Exit(1).  The method accepts the standard golang error-type.
+// The Fatal message-type is always active irrespective of lw-settings.
+// Note that you do not need to pass the newline escape code ("\n").
+// Usage Example:
+// lw.Fatal(e)
+func Fatal(e error) {
+	logerr(2, std, LevelFatal, "", e)
+}
+
+// Fatalf writes a Fatal log-entry based on the current lw settings and then terminates
 // the application via os.Exit(1).  The method accepts a printf-type formatted string
 // and a list of operands to use in the verb-replacement.
 // The Fatal message-type is always active irrespective of lw-settings.
 // Note that you do not need to pass the newline escape code ("\n").
 // Usage Example:
-// lw.Fatal("This is a test %s with the number %d", "MESSAGE", 42)
-func Fatal(e error) {
-	_, f, line, ok := runtime.Caller(1)
-	if ok {
-		io.WriteString(logWriter.writer, logWriter.fatalTxt+time.Now().Format(time.RFC3339Nano)+"\t"+e.Error()+"\t"+f+" line:"+strconv.Itoa(line)+"\n")
-		os.Exit(1)
-	}
-	io.WriteString(logWriter.writer, logWriter.fatalTxt+time.Now().Format(time.RFC3339Nano)+"\t"+e.Error()+"\n")
-	os.Exit(1)
+// lw.Fatalf("This is a test %s with the number %d", "MESSAGE", 42)
+func Fatalf(s string, i ...interface{}) {
+	logf(2, std, LevelFatal, s, i...)
+}
+
+// FatalE writes a Fatal log-entry carrying an error and then terminates the application
+// via os.Exit(1).  The Printf-type formatted message is followed by the rendered error.
+// Usage Example:
+// lw.FatalE(e, "Could not open %s", "db.sqlite")
+func FatalE(e error, s string, i ...interface{}) {
+	loge(2, std, LevelFatal, e, s, i...)
+}
+
+// Fatalw writes a Fatal log-entry followed by a list of alternating keys and values and
+// then terminates the application via os.Exit(1).
+// Usage Example:
+// lw.Fatalw("Could not bind", "port", 8080)
+func Fatalw(s string, kv ...interface{}) {
+	logw(2, std, LevelFatal, s, kv...)
 }
@@ -0,0 +1,261 @@
+// Package lwaudit provides an append-only audit log with tamper-evident
+// hash chaining.  Each entry is written as a line of JSON carrying a
+// sequence number and the hash of the previous entry, followed by its own
+// hash, an HMAC-SHA256 over the entry computed with a secret key.  Changing,
+// removing, inserting or reordering entries breaks the chain, which Verify
+// detects given the key.  Removing entries from the end of the log cannot
+// be detected from the log alone; record the sequence number and hash of
+// the last entry elsewhere to detect that.
+//
+// Unlike lw log-entries, audit entries are never sampled, deduplicated or
+// disabled, and write failures are reported to the caller.
+package lwaudit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// hashSuffix is the start of the hash member ending each entry.
+const hashSuffix = `,"hash":"`
+
+// hashLen is the length of a hex-encoded HMAC-SHA256.
+const hashLen = 2 * sha256.Size
+
+// Entry is a decoded audit log entry.
+type Entry struct {
+	Seq     uint64                     `json:"seq"`
+	Time    time.Time                  `json:"time"`
+	Message string                     `json:"msg"`
+	Fields  map[string]json.RawMessage `json:"fields,omitempty"`
+	Prev    string                     `json:"prev"`
+	Hash    string                     `json:"-"`
+}
+
+// Logger writes audit entries.  A Logger is safe for concurrent use.
+type Logger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	key   []byte
+	seq   uint64
+	prev  string
+	clock func() time.Time
+
+	// err is set once an entry has been written partially, after which the
+	// chain cannot be continued.
+	err error
+}
+
+// New returns a Logger starting a new audit log on w, signed with key.
+// Usage Example:
+// audit := lwaudit.New(f, key)
+func New(w io.Writer, key []byte) *Logger {
+	return &Logger{w: w, key: append([]byte(nil), key...), clock: time.Now}
+}
+
+// OpenFile opens or creates the audit log file name for appending, signed
+// with key.  The existing entries are verified first, and the chain is
+// continued from the last one.  An error is returned if the existing
+// entries do not verify.
+// Usage Example:
+// audit, err := lwaudit.OpenFile("/var/log/app/audit.log", key)
+func OpenFile(name string, key []byte) (*Logger, error) {
+	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	last, _, err := verify(f, key)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	l := New(f, key)
+	l.seq, l.prev = last.Seq, last.Hash
+	return l, nil
+}
+
+// Log writes an audit entry with message msg and the alternating keys and
+// values in kv.  Values are encoded as JSON; errors are written as their
+// message, and values that cannot be encoded are written as per
+// fmt.Sprint.  If the underlying writer has a Sync method, it is called
+// after each entry.  An entry that was not written at all is not counted,
+// so that the following entry continues the chain.  An entry that was
+// written but could not be synced is counted, and the error is returned.
+// Once an entry has been written partially, the Logger refuses all further
+// entries, as they could not be verified.
+func (l *Logger) Log(msg string, kv ...interface{}) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	e := Entry{
+		Seq:     l.seq + 1,
+		Time:    l.clock().UTC(),
+		Message: msg,
+		Fields:  fields(kv),
+		Prev:    l.prev,
+	}
+	body, err := json.Marshal(&e)
+	if err != nil {
+		return err
+	}
+	h := sum(l.key, body)
+	line := make([]byte, 0, len(body)+len(hashSuffix)+hashLen+3)
+	line = append(line, body[:len(body)-1]...)
+	line = append(line, hashSuffix...)
+	line = append(line, h...)
+	line = append(line, "\"}\n"...)
+	n, err := l.w.Write(line)
+	if n < len(line) {
+		if err == nil {
+			err = io.ErrShortWrite
+		}
+		if n > 0 {
+			l.err = fmt.Errorf("lwaudit: entry %d written partially: %w", e.Seq, err)
+			return l.err
+		}
+		return err
+	}
+	l.seq, l.prev = e.Seq, h
+	if s, ok := l.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if c, ok := l.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Seq returns the sequence number of the last entry written.
+func (l *Logger) Seq() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.seq
+}
+
+// fields converts the alternating keys and values in kv to JSON.  A
+// trailing key without a value is stored under the key "!BADKEY".
+func fields(kv []interface{}) map[string]json.RawMessage {
+	if len(kv) == 0 {
+		return nil
+	}
+	m := make(map[string]json.RawMessage, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i += 2 {
+		if i+1 == len(kv) {
+			m["!BADKEY"] = value(kv[i])
+			break
+		}
+		k, ok := kv[i].(string)
+		if !ok {
+			k = fmt.Sprint(kv[i])
+		}
+		m[k] = value(kv[i+1])
+	}
+	return m
+}
+
+// value encodes v as JSON.
+func value(v interface{}) json.RawMessage {
+	if e, ok := v.(error); ok {
+		v = e.Error()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(v))
+	}
+	return b
+}
+
+// sum returns the hex-encoded HMAC-SHA256 of body using key.
+func sum(key, body []byte) string {
+	m := hmac.New(sha256.New, key)
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// VerifyError reports the first entry of an audit log failing
+// verification.
+type VerifyError struct {
+	Line   int
+	Seq    uint64
+	Reason string
+}
+
+func (e *VerifyError) Error() string {
+	return "lwaudit: line " + strconv.Itoa(e.Line) + " (seq " + strconv.FormatUint(e.Seq, 10) + "): " + e.Reason
+}
+
+// Verify reads an audit log from r and verifies its entries using key.  It
+// returns the number of entries that verified.  If an entry is malformed,
+// fails its hash check, is out of sequence or does not chain to its
+// predecessor, a *VerifyError identifying the first such entry is returned.
+// Usage Example:
+// n, err := lwaudit.Verify(f, key)
+func Verify(r io.Reader, key []byte) (int, error) {
+	_, n, err := verify(r, key)
+	return n, err
+}
+
+// verify verifies the audit log read from r, and returns its last entry
+// and number of entries.
+func verify(r io.Reader, key []byte) (Entry, int, error) {
+	var last Entry
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 16<<20)
+	n := 0
+	for sc.Scan() {
+		n++
+		e, err := decode(sc.Bytes(), key)
+		if err == nil && e.Seq != last.Seq+1 {
+			err = fmt.Errorf("expected seq %d, entries are missing or out of order", last.Seq+1)
+		}
+		if err == nil && e.Prev != last.Hash {
+			err = errors.New("does not chain to the previous entry")
+		}
+		if err != nil {
+			return last, n - 1, &VerifyError{Line: n, Seq: e.Seq, Reason: err.Error()}
+		}
+		last = e
+	}
+	if err := sc.Err(); err != nil {
+		return last, n, err
+	}
+	return last, n, nil
+}
+
+// decode decodes and authenticates entry line using key.
+func decode(line, key []byte) (Entry, error) {
+	var e Entry
+	i := len(line) - len(hashSuffix) - hashLen - 2
+	if i <= 0 || !bytes.Equal(line[i:i+len(hashSuffix)], []byte(hashSuffix)) || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return e, errors.New("malformed entry")
+	}
+	body := append(append([]byte(nil), line[:i]...), '}')
+	if err := json.Unmarshal(body, &e); err != nil {
+		return e, errors.New("malformed entry: " + err.Error())
+	}
+	e.Hash = string(line[i+len(hashSuffix) : len(line)-2])
+	if !hmac.Equal([]byte(sum(key, body)), []byte(e.Hash)) {
+		return e, errors.New("hash mismatch, the entry was modified or the key is wrong")
+	}
+	return e, nil
+}
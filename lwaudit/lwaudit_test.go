@@ -0,0 +1,119 @@
+package lwaudit
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testKey = []byte("secret")
+
+// testLog returns an audit log of n entries.
+func testLog(t *testing.T, n int) []string {
+	var buf bytes.Buffer
+	l := New(&buf, testKey)
+	for i := 0; i < n; i++ {
+		if err := l.Log("user updated", "user", "alice", "n", i, "err", errors.New("none")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return strings.SplitAfter(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func TestVerify(t *testing.T) {
+	lines := testLog(t, 4)
+	if !strings.Contains(lines[0], `"seq":1,`) || !strings.Contains(lines[0], `"err":"none"`) {
+		t.Errorf("unexpected entry %q", lines[0])
+	}
+	n, err := Verify(strings.NewReader(strings.Join(lines, "")), testKey)
+	if n != 4 || err != nil {
+		t.Fatalf("expected 4 entries to verify, got %d, %v", n, err)
+	}
+
+	for _, c := range []struct {
+		name   string
+		lines  []string
+		key    []byte
+		line   int
+		reason string
+	}{
+		{"modified", []string{lines[0], strings.Replace(lines[1], "alice", "mallory", 1), lines[2]}, testKey, 2, "hash mismatch"},
+		{"missing", []string{lines[0], lines[2], lines[3]}, testKey, 2, "expected seq 2"},
+		{"reordered", []string{lines[0], lines[2], lines[1]}, testKey, 2, "expected seq 2"},
+		{"truncated", []string{lines[0], lines[1][:20] + "\n"}, testKey, 2, "malformed"},
+		{"wrong key", lines, []byte("other"), 1, "hash mismatch"},
+	} {
+		n, err := Verify(strings.NewReader(strings.Join(c.lines, "")), c.key)
+		var ve *VerifyError
+		if !errors.As(err, &ve) {
+			t.Errorf("%s: expected a VerifyError, got %v", c.name, err)
+			continue
+		}
+		if ve.Line != c.line || n != c.line-1 || !strings.Contains(ve.Reason, c.reason) {
+			t.Errorf("%s: unexpected error %v after %d entries", c.name, err, n)
+		}
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "audit.log")
+	for i := 0; i < 2; i++ {
+		l, err := OpenFile(name, testKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.Log("login", "user", "alice")
+		l.Log("logout", "user", "alice")
+		if l.Seq() != uint64(2*(i+1)) {
+			t.Errorf("expected seq %d, got %d", 2*(i+1), l.Seq())
+		}
+		if err := l.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := OpenFile(name, []byte("other")); err == nil {
+		t.Error("expected OpenFile to refuse a log failing verification")
+	}
+}
+
+// faultyWriter writes at most max bytes per call and fails to sync.
+type faultyWriter struct {
+	bytes.Buffer
+	max int
+}
+
+func (w *faultyWriter) Write(p []byte) (int, error) {
+	if w.max > 0 && len(p) > w.max {
+		n, _ := w.Buffer.Write(p[:w.max])
+		return n, errors.New("disk full")
+	}
+	return w.Buffer.Write(p)
+}
+
+func (w *faultyWriter) Sync() error {
+	return errors.New("sync not supported")
+}
+
+func TestLogWriteErrors(t *testing.T) {
+	w := &faultyWriter{}
+	l := New(w, testKey)
+	for i := 0; i < 3; i++ {
+		if err := l.Log("written", "n", i); err == nil {
+			t.Error("expected the sync error to be returned")
+		}
+	}
+	if n, err := Verify(bytes.NewReader(w.Bytes()), testKey); n != 3 || err != nil {
+		t.Errorf("expected 3 entries to verify, got %d, %v", n, err)
+	}
+
+	w.max = 10
+	if err := l.Log("torn"); err == nil || !strings.Contains(err.Error(), "written partially") {
+		t.Errorf("expected a partial write error, got %v", err)
+	}
+	w.max = 0
+	if err := l.Log("refused"); err == nil || l.Seq() != 3 {
+		t.Errorf("expected further entries to be refused, got %v at seq %d", err, l.Seq())
+	}
+}
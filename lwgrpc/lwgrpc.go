@@ -0,0 +1,133 @@
+// Package lwgrpc provides a logger satisfying the grpclog.LoggerV2 and
+// grpclog.DepthLoggerV2 interfaces of google.golang.org/grpc, writing via
+// lw.  The interfaces are satisfied structurally, so this package does not
+// depend on gRPC.
+// Usage Example:
+// grpclog.SetLoggerV2(lwgrpc.New(lw.Named("grpc")))
+package lwgrpc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/1414C/lw"
+)
+
+// Logger writes the log messages of gRPC via a lw.Logger.  gRPC verbosity
+// levels are mapped to lw message types: V(0) is enabled when Info is
+// enabled, V(1) when Debug is enabled and V(2) and above when Trace is
+// enabled.
+type Logger struct {
+	lg *lw.Logger
+}
+
+// New returns a Logger writing via lw Logger lg.
+func New(lg *lw.Logger) *Logger {
+	return &Logger{lg: lg.WithCallerSkip(1)}
+}
+
+// sprintln renders args in the manner of fmt.Sprintln, without the
+// trailing newline.
+func sprintln(args []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+// Info writes args as an Info log-entry, in the manner of fmt.Print.
+func (l *Logger) Info(args ...interface{}) {
+	l.lg.Info(args...)
+}
+
+// Infoln writes args as an Info log-entry, in the manner of fmt.Println.
+func (l *Logger) Infoln(args ...interface{}) {
+	l.lg.Info(sprintln(args))
+}
+
+// Infof writes a formatted Info log-entry.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.lg.Infof(format, args...)
+}
+
+// InfoDepth writes args as an Info log-entry, skipping depth additional
+// frames when reporting the caller location.
+func (l *Logger) InfoDepth(depth int, args ...interface{}) {
+	l.lg.WithCallerSkip(depth).Info(args...)
+}
+
+// Warning writes args as a Warning log-entry, in the manner of fmt.Print.
+func (l *Logger) Warning(args ...interface{}) {
+	l.lg.Warning(args...)
+}
+
+// Warningln writes args as a Warning log-entry, in the manner of
+// fmt.Println.
+func (l *Logger) Warningln(args ...interface{}) {
+	l.lg.Warning(sprintln(args))
+}
+
+// Warningf writes a formatted Warning log-entry.
+func (l *Logger) Warningf(format string, args ...interface{}) {
+	l.lg.Warningf(format, args...)
+}
+
+// WarningDepth writes args as a Warning log-entry, skipping depth
+// additional frames when reporting the caller location.
+func (l *Logger) WarningDepth(depth int, args ...interface{}) {
+	l.lg.WithCallerSkip(depth).Warning(args...)
+}
+
+// Error writes args as an Error log-entry, in the manner of fmt.Print.
+func (l *Logger) Error(args ...interface{}) {
+	l.lg.Errorw(fmt.Sprint(args...))
+}
+
+// Errorln writes args as an Error log-entry, in the manner of fmt.Println.
+func (l *Logger) Errorln(args ...interface{}) {
+	l.lg.Errorw(sprintln(args))
+}
+
+// Errorf writes a formatted Error log-entry.
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.lg.Errorf(format, args...)
+}
+
+// ErrorDepth writes args as an Error log-entry, skipping depth additional
+// frames when reporting the caller location.
+func (l *Logger) ErrorDepth(depth int, args ...interface{}) {
+	l.lg.WithCallerSkip(depth).Errorw(fmt.Sprint(args...))
+}
+
+// Fatal writes args as a Fatal log-entry, in the manner of fmt.Print, and
+// terminates the application.
+func (l *Logger) Fatal(args ...interface{}) {
+	l.lg.Fatalw(fmt.Sprint(args...))
+}
+
+// Fatalln writes args as a Fatal log-entry, in the manner of fmt.Println,
+// and terminates the application.
+func (l *Logger) Fatalln(args ...interface{}) {
+	l.lg.Fatalw(sprintln(args))
+}
+
+// Fatalf writes a formatted Fatal log-entry and terminates the
+// application.
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.lg.Fatalf(format, args...)
+}
+
+// FatalDepth writes args as a Fatal log-entry, skipping depth additional
+// frames when reporting the caller location, and terminates the
+// application.
+func (l *Logger) FatalDepth(depth int, args ...interface{}) {
+	l.lg.WithCallerSkip(depth).Fatalw(fmt.Sprint(args...))
+}
+
+// V reports whether verbosity level v is enabled.
+func (l *Logger) V(v int) bool {
+	switch {
+	case v <= 0:
+		return l.lg.InfoEnabled()
+	case v == 1:
+		return l.lg.DebugEnabled()
+	}
+	return l.lg.TraceEnabled()
+}
@@ -0,0 +1,78 @@
+package lwgrpc
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/1414C/lw"
+)
+
+// loggerV2 is the grpclog.LoggerV2 interface of google.golang.org/grpc.
+type loggerV2 interface {
+	Info(args ...interface{})
+	Infoln(args ...interface{})
+	Infof(format string, args ...interface{})
+	Warning(args ...interface{})
+	Warningln(args ...interface{})
+	Warningf(format string, args ...interface{})
+	Error(args ...interface{})
+	Errorln(args ...interface{})
+	Errorf(format string, args ...interface{})
+	Fatal(args ...interface{})
+	Fatalln(args ...interface{})
+	Fatalf(format string, args ...interface{})
+	V(l int) bool
+}
+
+// depthLoggerV2 is the grpclog.DepthLoggerV2 interface of
+// google.golang.org/grpc.
+type depthLoggerV2 interface {
+	loggerV2
+	InfoDepth(depth int, args ...interface{})
+	WarningDepth(depth int, args ...interface{})
+	ErrorDepth(depth int, args ...interface{})
+	FatalDepth(depth int, args ...interface{})
+}
+
+var _ depthLoggerV2 = (*Logger)(nil)
+
+// depth calls l.InfoDepth as gRPC does, from within its own logging
+// function.
+func depth(l *Logger, msg string) {
+	l.InfoDepth(1, msg)
+}
+
+func TestLogger(t *testing.T) {
+	defer lw.DisableAndReset()
+	var buf bytes.Buffer
+	lw.InitWithSettings(lw.LogWriterState{
+		Enabled:        true,
+		LocEnabled:     true,
+		InfoEnabled:    true,
+		WarningEnabled: true,
+		ErrorEnabled:   true,
+		TimeFormat:     lw.TimeFormatNone,
+		LocMode:        lw.LocShort,
+	}, &buf)
+
+	var l loggerV2 = New(lw.Named("grpc"))
+	_, _, line, _ := runtime.Caller(0)
+	l.Infoln("channel", 1, "ready")
+	l.Warningf("retry %d", 2)
+	l.Error("transport", " closed")
+	depth(l.(*Logger), "picked")
+
+	loc := "\tlwgrpc_test.go line:"
+	want := "INFO:\t[grpc] channel 1 ready" + loc + strconv.Itoa(line+1) + "\n" +
+		"WARNING:\t[grpc] retry 2" + loc + strconv.Itoa(line+2) + "\n" +
+		"ERROR:\t[grpc] transport closed" + loc + strconv.Itoa(line+3) + "\n" +
+		"INFO:\t[grpc] picked" + loc + strconv.Itoa(line+4) + "\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	if !l.V(0) || l.V(2) {
+		t.Error("expected the verbosity levels to follow the lw activation settings")
+	}
+}
@@ -0,0 +1,90 @@
+// Package lwlogr provides a logr.LogSink writing via lw, so that libraries
+// logging through github.com/go-logr/logr share the output and activation
+// settings of lw.  logr verbosity levels are mapped to lw message types:
+// V(0) is written as Info, V(1) as Debug and V(2) and above as Trace.
+// Errors are written as Error log-entries carrying the error as field
+// "error".
+package lwlogr
+
+import (
+	"github.com/go-logr/logr"
+
+	"github.com/1414C/lw"
+)
+
+// sink is a logr.LogSink writing via a lw.Logger.
+type sink struct {
+	lg *lw.Logger
+}
+
+// New returns a logr.Logger writing via the package-level lw
+// configuration.
+// Usage Example:
+// ctrl.SetLogger(lwlogr.New())
+func New() logr.Logger {
+	return NewWithLogger(lw.New())
+}
+
+// NewWithLogger returns a logr.Logger writing via lw Logger lg, including
+// its name and fields.
+func NewWithLogger(lg *lw.Logger) logr.Logger {
+	return logr.New(&sink{lg: lg})
+}
+
+// level returns the lw message type of logr verbosity level v.
+func level(v int) lw.Level {
+	switch {
+	case v <= 0:
+		return lw.LevelInfo
+	case v == 1:
+		return lw.LevelDebug
+	}
+	return lw.LevelTrace
+}
+
+// Init skips the frames added by logr when reporting the caller location.
+func (s *sink) Init(info logr.RuntimeInfo) {
+	s.lg = s.lg.WithCallerSkip(info.CallDepth + 1)
+}
+
+// Enabled reports whether lw writes log-entries of verbosity level v.
+func (s *sink) Enabled(v int) bool {
+	return s.lg.Enabled(level(v))
+}
+
+// Info writes msg and the alternating keys and values in kv at verbosity
+// level v.
+func (s *sink) Info(v int, msg string, kv ...interface{}) {
+	switch level(v) {
+	case lw.LevelInfo:
+		s.lg.Infow(msg, kv...)
+	case lw.LevelDebug:
+		s.lg.Debugw(msg, kv...)
+	default:
+		s.lg.Tracew(msg, kv...)
+	}
+}
+
+// Error writes msg, error err and the alternating keys and values in kv as
+// an Error log-entry.
+func (s *sink) Error(err error, msg string, kv ...interface{}) {
+	s.lg.Errorw(msg, append([]interface{}{"error", err}, kv...)...)
+}
+
+// WithValues returns a LogSink writing the alternating keys and values in
+// kv with each log-entry.
+func (s *sink) WithValues(kv ...interface{}) logr.LogSink {
+	return &sink{lg: s.lg.With(kv...)}
+}
+
+// WithName returns a LogSink whose name is the name of s and name joined
+// by a dot.
+func (s *sink) WithName(name string) logr.LogSink {
+	return &sink{lg: s.lg.Named(name)}
+}
+
+// WithCallDepth returns a LogSink skipping depth additional frames when
+// reporting the caller location.
+func (s *sink) WithCallDepth(depth int) logr.LogSink {
+	return &sink{lg: s.lg.WithCallerSkip(depth)}
+}
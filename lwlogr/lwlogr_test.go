@@ -0,0 +1,43 @@
+package lwlogr
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/1414C/lw"
+)
+
+func TestLogr(t *testing.T) {
+	defer lw.DisableAndReset()
+	var buf bytes.Buffer
+	lw.InitWithSettings(lw.LogWriterState{
+		Enabled:      true,
+		LocEnabled:   true,
+		InfoEnabled:  true,
+		DebugEnabled: true,
+		ErrorEnabled: true,
+		TimeFormat:   lw.TimeFormatNone,
+		LocMode:      lw.LocShort,
+	}, &buf)
+
+	log := New().WithName("ctrl").WithValues("ns", "default")
+	_, _, line, _ := runtime.Caller(0)
+	log.Info("reconciled", "objects", 3)
+	log.V(1).Info("cache hit")
+	log.V(2).Info("dropped")
+	log.Error(errors.New("conflict"), "update failed", "retry", true)
+
+	loc := "\tlwlogr_test.go line:"
+	want := "INFO:\t[ctrl] reconciled ns=default objects=3" + loc + strconv.Itoa(line+1) + "\n" +
+		"DEBUG:\t[ctrl] cache hit ns=default" + loc + strconv.Itoa(line+2) + "\n" +
+		"ERROR:\t[ctrl] update failed ns=default error=conflict retry=true" + loc + strconv.Itoa(line+4) + "\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	if !log.V(1).Enabled() || log.V(2).Enabled() {
+		t.Error("expected the verbosity levels to follow the lw activation settings")
+	}
+}
@@ -0,0 +1,67 @@
+// Package lwprintf provides a logger satisfying the Printf, Print and
+// Println style interfaces expected by many libraries, such as database
+// drivers, writing via lw at a fixed message type.
+// Usage Example:
+// mysql.SetLogger(lwprintf.New(lw.Named("mysql"), lw.LevelWarning))
+package lwprintf
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/1414C/lw"
+)
+
+// Logger writes messages via a lw.Logger as log-entries of a fixed message
+// type.
+type Logger struct {
+	lg *lw.Logger
+	l  lw.Level
+}
+
+// New returns a Logger writing via lw Logger lg as log-entries of message
+// type l.  A Logger created with lw.LevelFatal terminates the application
+// after writing.
+func New(lg *lw.Logger, l lw.Level) *Logger {
+	return &Logger{lg: lg.WithCallerSkip(2), l: l}
+}
+
+// Printf writes a formatted log-entry.
+func (l *Logger) Printf(format string, v ...interface{}) {
+	if l.lg.Enabled(l.l) {
+		l.write(fmt.Sprintf(format, v...))
+	}
+}
+
+// Print writes v in the manner of fmt.Print.
+func (l *Logger) Print(v ...interface{}) {
+	if l.lg.Enabled(l.l) {
+		l.write(fmt.Sprint(v...))
+	}
+}
+
+// Println writes v in the manner of fmt.Println.
+func (l *Logger) Println(v ...interface{}) {
+	if l.lg.Enabled(l.l) {
+		l.write(fmt.Sprintln(v...))
+	}
+}
+
+// write writes message m, without any trailing newline, as a log-entry.
+func (l *Logger) write(m string) {
+	m = strings.TrimSuffix(m, "\n")
+	switch l.l {
+	case lw.LevelTrace:
+		l.lg.Tracew(m)
+	case lw.LevelDebug:
+		l.lg.Debugw(m)
+	case lw.LevelInfo:
+		l.lg.Infow(m)
+	case lw.LevelWarning:
+		l.lg.Warningw(m)
+	case lw.LevelError:
+		l.lg.Errorw(m)
+	case lw.LevelFatal:
+		l.lg.Fatalw(m)
+	}
+}
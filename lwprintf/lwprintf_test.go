@@ -0,0 +1,41 @@
+package lwprintf
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/1414C/lw"
+)
+
+// printfer is the interface expected by many database drivers.
+type printfer interface {
+	Printf(format string, v ...interface{})
+}
+
+func TestLogger(t *testing.T) {
+	defer lw.DisableAndReset()
+	var buf bytes.Buffer
+	lw.InitWithSettings(lw.LogWriterState{
+		Enabled:        true,
+		LocEnabled:     true,
+		WarningEnabled: true,
+		TimeFormat:     lw.TimeFormatNone,
+		LocMode:        lw.LocShort,
+	}, &buf)
+
+	var p printfer = New(lw.Named("mysql"), lw.LevelWarning)
+	l := p.(*Logger)
+	_, _, line, _ := runtime.Caller(0)
+	p.Printf("invalid connection: %v\n", "EOF")
+	l.Println("busy buffer")
+	New(lw.New(), lw.LevelDebug).Print("dropped")
+
+	loc := "\tlwprintf_test.go line:"
+	want := "WARNING:\t[mysql] invalid connection: EOF" + loc + strconv.Itoa(line+1) + "\n" +
+		"WARNING:\t[mysql] busy buffer" + loc + strconv.Itoa(line+2) + "\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
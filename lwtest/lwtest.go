@@ -0,0 +1,141 @@
+// Package lwtest provides helpers for testing code that logs via lw.  It
+// captures the Records written by lw in memory so that tests can assert on
+// them, and routes the text output of lw through testing.TB.Log so that it
+// is attributed to the test that produced it.
+package lwtest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/1414C/lw"
+)
+
+// recorder is a lw.Hook capturing all Records.
+type recorder struct {
+	mu      sync.Mutex
+	records []lw.Record
+}
+
+var rec recorder
+
+// Run captures Record r.
+func (r *recorder) Run(lr lw.Record) (lw.Record, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, lr)
+	return lr, true
+}
+
+// Start configures lw for use in the test tb.  All message types are
+// enabled, the text output is written via tb.Log, previously captured
+// Records are discarded and Records are captured from here on.  Start
+// replaces any hooks registered with lw, so call it at the beginning of
+// each test, before registering hooks of your own.  When the test ends,
+// the hooks are removed and lw is disabled and reset, so that later tests
+// do not log via tb.
+// Usage Example:
+// lwtest.Start(t)
+// doWork()
+// lwtest.AssertLogged(t, lw.LevelError, "connection refused")
+func Start(tb testing.TB) {
+	lw.InitWithSettings(lw.LogWriterState{
+		Enabled:        true,
+		LocEnabled:     true,
+		TraceEnabled:   true,
+		InfoEnabled:    true,
+		WarningEnabled: true,
+		DebugEnabled:   true,
+		ErrorEnabled:   true,
+		LocMode:        lw.LocShort,
+	}, NewWriter(tb))
+	lw.ResetHooks()
+	lw.AddHook(lw.LevelTrace, &rec)
+	Reset()
+	tb.Cleanup(func() {
+		lw.ResetHooks()
+		lw.DisableAndReset()
+	})
+}
+
+// Records returns a copy of the Records captured since the last call to
+// Start or Reset.
+func Records() []lw.Record {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	r := make([]lw.Record, len(rec.records))
+	copy(r, rec.records)
+	return r
+}
+
+// Reset discards all captured Records.
+func Reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.records = nil
+}
+
+// Logged reports whether a Record of message type l containing substr in
+// its message or fields has been captured.
+func Logged(l lw.Level, substr string) bool {
+	for _, r := range Records() {
+		if r.Level == l && strings.Contains(text(r), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// AssertLogged marks tb as failed if no Record of message type l containing
+// substr in its message or fields has been captured.
+func AssertLogged(tb testing.TB, l lw.Level, substr string) bool {
+	tb.Helper()
+	if !Logged(l, substr) {
+		tb.Errorf("lwtest: no %v record containing %q was logged", l, substr)
+		return false
+	}
+	return true
+}
+
+// AssertNotLogged marks tb as failed if a Record of message type l
+// containing substr in its message or fields has been captured.
+func AssertNotLogged(tb testing.TB, l lw.Level, substr string) bool {
+	tb.Helper()
+	if Logged(l, substr) {
+		tb.Errorf("lwtest: unexpected %v record containing %q was logged", l, substr)
+		return false
+	}
+	return true
+}
+
+// text renders the message and fields of r.
+func text(r lw.Record) string {
+	var b strings.Builder
+	b.WriteString(r.Message)
+	for _, f := range r.Fields {
+		b.WriteString(" " + f.Key + "=" + fmt.Sprint(f.Value))
+	}
+	return b.String()
+}
+
+// tbWriter writes lw output via testing.TB.Log.
+type tbWriter struct {
+	tb testing.TB
+}
+
+// NewWriter returns an io.Writer writing each log-entry via tb.Log.
+// Usage Example:
+// lw.SetWriter(lwtest.NewWriter(t))
+func NewWriter(tb testing.TB) io.Writer {
+	return tbWriter{tb: tb}
+}
+
+// Write writes p via tb.Log, removing the trailing newline.
+func (w tbWriter) Write(p []byte) (int, error) {
+	w.tb.Helper()
+	w.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
@@ -0,0 +1,97 @@
+package lwtest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/1414C/lw"
+)
+
+// fakeTB records the calls made by the lwtest helpers.
+type fakeTB struct {
+	testing.TB
+	logs   []string
+	failed bool
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Log(args ...interface{}) {
+	f.logs = append(f.logs, args[0].(string))
+}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.failed = true
+}
+
+func TestRecorder(t *testing.T) {
+	defer lw.DisableAndReset()
+	Start(t)
+
+	lw.Named("db").Errorw("query failed", "table", "users")
+	lw.Infof("served %d requests", 3)
+	lw.ErrorE(errors.New("connection refused"), "dial")
+
+	r := Records()
+	if len(r) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(r))
+	}
+	if r[0].Level != lw.LevelError || r[0].Logger != "db" || r[0].Message != "query failed" {
+		t.Errorf("unexpected record %+v", r[0])
+	}
+	AssertLogged(t, lw.LevelError, "table=users")
+	AssertLogged(t, lw.LevelInfo, "served 3")
+	AssertLogged(t, lw.LevelError, "connection refused")
+	AssertNotLogged(t, lw.LevelWarning, "served")
+
+	f := &fakeTB{}
+	if AssertLogged(f, lw.LevelDebug, "served") || !f.failed {
+		t.Error("expected AssertLogged to fail")
+	}
+	f = &fakeTB{}
+	if AssertNotLogged(f, lw.LevelInfo, "served") || !f.failed {
+		t.Error("expected AssertNotLogged to fail")
+	}
+
+	Reset()
+	if len(Records()) != 0 || Logged(lw.LevelInfo, "served") {
+		t.Error("expected Reset to discard the records")
+	}
+}
+
+func TestStartCleanup(t *testing.T) {
+	defer lw.DisableAndReset()
+	f := &fakeTB{}
+	t.Run("logging", func(t *testing.T) {
+		Start(t)
+		lw.Info("within the test")
+		lw.SetWriter(NewWriter(f))
+	})
+	lw.Info("after the test")
+	if lw.InfoEnabled() || len(f.logs) != 0 {
+		t.Errorf("expected lw to be reset once the test ended, got %q", f.logs)
+	}
+	Reset()
+	lw.InitWithSettings(lw.LogWriterState{Enabled: true, InfoEnabled: true}, NewWriter(f))
+	lw.Info("not recorded")
+	if len(Records()) != 0 {
+		t.Error("expected the recorder hook to be removed once the test ended")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	defer lw.DisableAndReset()
+	f := &fakeTB{}
+	lw.Enable(false, false, NewWriter(f))
+	lw.WarningEnable(true)
+	lw.SetTimeFormat(lw.TimeFormatNone)
+
+	lw.Warning("via t.Log")
+	if len(f.logs) != 1 || f.logs[0] != "WARNING:\tvia t.Log" {
+		t.Errorf("unexpected logs %q", f.logs)
+	}
+	if strings.HasSuffix(f.logs[0], "\n") {
+		t.Error("expected the trailing newline to be removed")
+	}
+}
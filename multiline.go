@@ -0,0 +1,95 @@
+package lw
+
+import "strings"
+
+// MultiLineMode determines how line breaks within the messages, errors and
+// field values of a log-entry are written.
+type MultiLineMode int
+
+const (
+	// MultiLineAsIs writes line breaks unchanged, so that a log-entry may
+	// span several lines (default).
+	MultiLineAsIs MultiLineMode = iota
+	// MultiLineEscape writes newlines, carriage returns and tabs as the
+	// escape sequences \n, \r and \t, keeping each log-entry on one line.
+	// Backslashes are written as \\, so that escaped line breaks can be
+	// told from literal ones.
+	MultiLineEscape
+	// MultiLineIndent starts each continuation line, including the causes
+	// of an error and the frames of a stack, with the marker
+	// MultiLineMarker, so that log parsers can attribute it to the
+	// preceding log-entry.
+	MultiLineIndent
+)
+
+// MultiLineMarker starts the continuation lines of a log-entry in
+// MultiLineIndent mode.
+const MultiLineMarker = "\t| "
+
+// SetMultiLine sets the handling of line breaks within messages, errors,
+// Logger names and field keys and values, and of the lines holding the
+// causes of an error and the stack that follow a log-entry.
+// Usage Example:
+// lw.SetMultiLine(lw.MultiLineEscape)
+func SetMultiLine(m MultiLineMode) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.multiLine = m
+}
+
+// appendLines appends s to b, applying the MultiLineMode.
+func appendLines(b []byte, s string) []byte {
+	switch logWriter.multiLine {
+	case MultiLineEscape:
+		if strings.IndexAny(s, "\n\r\t\\") < 0 {
+			return append(b, s...)
+		}
+		for i := 0; i < len(s); i++ {
+			switch c := s[i]; c {
+			case '\n':
+				b = append(b, `\n`...)
+			case '\r':
+				b = append(b, `\r`...)
+			case '\t':
+				b = append(b, `\t`...)
+			case '\\':
+				b = append(b, `\\`...)
+			default:
+				b = append(b, c)
+			}
+		}
+		return b
+	case MultiLineIndent:
+		for {
+			i := strings.IndexByte(s, '\n')
+			if i < 0 {
+				return append(b, s...)
+			}
+			b = append(b, s[:i+1]...)
+			b = append(b, MultiLineMarker...)
+			s = s[i+1:]
+		}
+	}
+	return append(b, s...)
+}
+
+// appendContinuation ends the first line of a log-entry in b and appends
+// the lines s following it, such as the causes of an error and the stack,
+// applying the MultiLineMode.  In MultiLineEscape mode the line breaks
+// between the lines are escaped, and in MultiLineIndent mode each line
+// starts with MultiLineMarker.
+func appendContinuation(b []byte, s string) []byte {
+	switch logWriter.multiLine {
+	case MultiLineEscape:
+		b = append(b, `\n`...)
+		b = appendLines(b, strings.TrimSuffix(s, "\n"))
+		return append(b, eol()...)
+	case MultiLineIndent:
+		b = append(b, eol()...)
+		b = append(b, MultiLineMarker...)
+		b = appendLines(b, strings.TrimSuffix(s, "\n"))
+		return append(b, '\n')
+	}
+	b = append(b, eol()...)
+	return append(b, s...)
+}
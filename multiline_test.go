@@ -0,0 +1,114 @@
+package lw
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMultiLine(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:      true,
+		InfoEnabled:  true,
+		ErrorEnabled: true,
+		ErrorDetail:  true,
+		TimeFormat:   TimeFormatNone,
+	}, &buf)
+
+	tests := []struct {
+		mode MultiLineMode
+		want string
+	}{
+		{MultiLineAsIs, "INFO:\tSELECT *\n\tFROM t query=a\nb err=x\ty\n" +
+			"ERROR:\tload: open\nfailed type=*fmt.wrapError\n\tcause: open\nfailed type=*errors.errorString\n"},
+		{MultiLineEscape, "INFO:\tSELECT *\\n\\tFROM t query=a\\nb err=x\\ty\n" +
+			"ERROR:\tload: open\\nfailed type=*fmt.wrapError\\n\\tcause: open\\nfailed type=*errors.errorString\n"},
+		{MultiLineIndent, "INFO:\tSELECT *\n\t| \tFROM t query=a\n\t| b err=x\ty\n" +
+			"ERROR:\tload: open\n\t| failed type=*fmt.wrapError\n\t| \tcause: open\n\t| failed type=*errors.errorString\n"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		SetMultiLine(tt.mode)
+		Infow("SELECT *\n\tFROM t", "query", "a\nb", "err", errors.New("x\ty"))
+		Error(fmt.Errorf("load: %w", errors.New("open\nfailed")))
+		if buf.String() != tt.want {
+			t.Errorf("mode %d: got %q, want %q", tt.mode, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestMultiLineNames(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{Enabled: true, InfoEnabled: true, TimeFormat: TimeFormatNone}, &buf)
+
+	tests := []struct {
+		mode MultiLineMode
+		want string
+	}{
+		{MultiLineEscape, "INFO:\t" + `a\\nb\nc` + "\n" +
+			"INFO:\t" + `[x\ny] msg k\na=v\\t` + "\n"},
+		{MultiLineIndent, "INFO:\ta\\nb\n\t| c\n" +
+			"INFO:\t[x\n\t| y] msg k\n\t| a=v\\t\n"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		SetMultiLine(tt.mode)
+		Info("a\\nb", "\n", "c")
+		Named("x\ny").Infow("msg", "k\na", "v\\t")
+		if buf.String() != tt.want {
+			t.Errorf("mode %d: got %q, want %q", tt.mode, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestMultiLineStack(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:      true,
+		ErrorEnabled: true,
+		ErrorDetail:  true,
+		StackEnabled: true,
+		TimeFormat:   TimeFormatNone,
+		LocMode:      LocShort,
+	}, &buf)
+	err := fmt.Errorf("load: %w", errors.Join(errors.New("open"), errors.New("read")))
+
+	SetMultiLine(MultiLineEscape)
+	Error(err)
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 || !strings.HasSuffix(out, "\n") {
+		t.Errorf("expected a single line, got %q", out)
+	}
+	if !strings.Contains(out, `\n\tcause: open\n`) || !strings.Contains(out, `\n\tlw.TestMultiLineStack\tmultiline_test.go line:`) {
+		t.Errorf("expected the escaped causes and stack, got %q", out)
+	}
+
+	buf.Reset()
+	SetMultiLine(MultiLineIndent)
+	Error(err)
+	ls := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	causes := []string{
+		"\t| read type=*fmt.wrapError",
+		"\t| \tcause: open",
+		"\t| read type=*errors.joinError",
+		"\t| \tcause: open type=*errors.errorString",
+		"\t| \tcause: read type=*errors.errorString",
+	}
+	if len(ls) < 7 || strings.Join(ls[1:6], "\n") != strings.Join(causes, "\n") {
+		t.Errorf("unexpected causes in %q", ls)
+	}
+	for _, l := range ls[1:] {
+		if !strings.HasPrefix(l, MultiLineMarker) {
+			t.Errorf("expected each continuation line to start with the marker, got %q", l)
+		}
+	}
+	if !strings.HasPrefix(ls[len(ls)-1], MultiLineMarker+"\t") || !strings.Contains(buf.String(), MultiLineMarker+"\tlw.TestMultiLineStack\tmultiline_test.go line:") {
+		t.Errorf("expected the marked stack, got %q", buf.String())
+	}
+}
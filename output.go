@@ -0,0 +1,182 @@
+package lw
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// enabled reports whether log-entries of message type l are currently
+// being written by Logger lg.  Fatal log-entries are always written.  If a
+// level override applies to the name of lg it takes precedence over the
+// per message-type activations.
+func enabled(lg *Logger, l Level) bool {
+	if l == LevelFatal {
+		return true
+	}
+	if !logWriter.enabled {
+		return false
+	}
+	if min, ok := levelOverride(lg.name); ok {
+		return l >= min
+	}
+	switch l {
+	case LevelTrace:
+		return logWriter.traceEnabled
+	case LevelDebug:
+		return logWriter.debugEnabled
+	case LevelInfo:
+		return logWriter.infoEnabled
+	case LevelWarning:
+		return logWriter.warningEnabled
+	case LevelError:
+		return logWriter.errorEnabled
+	}
+	return false
+}
+
+// label returns the message type text written at the start of a log-entry.
+func label(l Level) string {
+	switch l {
+	case LevelTrace:
+		return logWriter.traceTxt
+	case LevelDebug:
+		return logWriter.debugTxt
+	case LevelInfo:
+		return logWriter.infoTxt
+	case LevelWarning:
+		return logWriter.warnTxt
+	case LevelError:
+		return logWriter.errorTxt
+	}
+	return logWriter.fatalTxt
+}
+
+// output writes a log-entry of message type l for Logger lg, attributed to
+// the caller calldepth frames up plus the frames skipped by lg.  Message m
+// is preceded by the name of lg and followed by the fields of lg and the
+// key/value pairs in kv.  detail holds complete lines to be written after
+// the log-entry, such as the causes of an error.  Error and Fatal
+// log-entries are followed by the stack if stack capture is enabled.  The
+// resulting Record is redacted and passed to the registered hooks before
+// being written.
+func output(calldepth int, lg *Logger, l Level, m string, kv []interface{}, detail string) {
+	st := ""
+	if l >= LevelError {
+		st = stack(calldepth + 1 + lg.skip)
+	}
+	outputStack(calldepth+1, lg, l, m, kv, detail, st)
+}
+
+// outputStack writes a log-entry in the manner of output, followed by the
+// rendered stack st.
+func outputStack(calldepth int, lg *Logger, l Level, m string, kv []interface{}, detail, st string) {
+	e := getEncoder()
+	r := Record{Level: l, Time: now(), Logger: lg.name, Message: m, detail: detail, stack: st}
+	if len(lg.fields)+len(kv) > 0 {
+		e.fields = appendFields(appendFields(e.fields, lg.fields), kv)
+		resolveLazy(e.fields)
+		r.Fields = e.fields
+	}
+	hs := hooks.Load().([]hook)
+	if needCaller(hs) {
+		r.Caller = callerOf(calldepth + 1 + lg.skip)
+	}
+	emit(e, hs, &r)
+}
+
+// needCaller reports whether the caller location of a log-entry must be
+// determined, given hooks hs.
+func needCaller(hs []hook) bool {
+	return logWriter.locEnabled || logWriter.dedupWindow > 0 || len(hs) > 0
+}
+
+// emit redacts Record r built using encoder e, passes it to hooks hs and
+// writes it.  The application is terminated after writing a Fatal Record
+// and any pending summary log-entries.
+func emit(e *encoder, hs []hook, r *Record) {
+	l := r.Level
+	if logWriter.redactEnabled {
+		redactRecord(r)
+	}
+	if runHooks(hs, r) {
+		write(e, r)
+	}
+	putEncoder(e)
+	if l == LevelFatal {
+		Flush()
+		os.Exit(1)
+	}
+}
+
+// write renders Record r into the buffer of encoder e and writes it to the
+// current writer with a single call to Write.
+func write(e *encoder, r *Record) {
+	b, start, end := appendRecord(e.buf, r)
+	if logWriter.dedupWindow > 0 {
+		dedup.write(dedupKey{l: r.Level, m: string(b[start:end]), file: r.Caller.File, line: r.Caller.Line}, b)
+	} else {
+		logWriter.writer.Write(b)
+	}
+	e.buf = b
+}
+
+// logp writes a log-entry whose message is built from i in the manner of
+// fmt.Sprint.
+func logp(calldepth int, lg *Logger, l Level, i ...interface{}) {
+	if enabled(lg, l) {
+		m, ok := "", false
+		if len(i) == 1 {
+			m, ok = i[0].(string)
+		}
+		if !ok {
+			m = fmt.Sprint(i...)
+		}
+		if admit(calldepth+1, lg, l, m) {
+			output(calldepth+1, lg, l, m, nil, "")
+		}
+	}
+}
+
+// logf writes a log-entry whose message is built from format s and
+// operands i in the manner of fmt.Sprintf.
+func logf(calldepth int, lg *Logger, l Level, s string, i ...interface{}) {
+	if enabled(lg, l) && admit(calldepth+1, lg, l, s) {
+		m := s
+		if len(i) > 0 || strings.IndexByte(s, '%') >= 0 {
+			m = fmt.Sprintf(s, i...)
+		}
+		output(calldepth+1, lg, l, m, nil, "")
+	}
+}
+
+// loge writes a log-entry carrying error e.  The formatted message is
+// followed by the rendered error, separated by ": ".
+func loge(calldepth int, lg *Logger, l Level, e error, s string, i ...interface{}) {
+	if enabled(lg, l) && admit(calldepth+1, lg, l, s) {
+		m, c := errorText(e)
+		output(calldepth+1, lg, l, fmt.Sprintf(s, i...)+": "+m, nil, c)
+	}
+}
+
+// logw writes a log-entry with message s followed by the key/value pairs
+// in kv.
+func logw(calldepth int, lg *Logger, l Level, s string, kv ...interface{}) {
+	if enabled(lg, l) && admit(calldepth+1, lg, l, s) {
+		output(calldepth+1, lg, l, s, kv, "")
+	}
+}
+
+// logerr writes a log-entry whose message is the rendered error e,
+// preceded by prefix s if s is not empty.
+func logerr(calldepth int, lg *Logger, l Level, s string, e error) {
+	if enabled(lg, l) {
+		m, c := errorText(e)
+		if s != "" {
+			m = s + " " + m
+		}
+		if admit(calldepth+1, lg, l, m) {
+			output(calldepth+1, lg, l, m, nil, c)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package lw
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestLevelVariants(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		TraceEnabled:   true,
+		DebugEnabled:   true,
+		InfoEnabled:    true,
+		WarningEnabled: true,
+		ErrorEnabled:   true,
+		TimeFormat:     TimeFormatNone,
+	}, &buf)
+	e := errors.New("boom")
+	l := New()
+
+	tests := []struct {
+		call func()
+		want string
+	}{
+		{func() { Info("plain ", 42, " ", true) }, "INFO:\tplain 42 true\n"},
+		{func() { Tracef("formatted %d", 42) }, "TRACE:\tformatted 42\n"},
+		{func() { DebugE(e, "loading %s", "cfg") }, "DEBUG:\tloading cfg: boom\n"},
+		{func() { Warningw("login", "user", 42, "ok", false) }, "WARNING:\tlogin user=42 ok=false\n"},
+		{func() { Infow("odd", "user") }, "INFO:\todd !BADKEY=user\n"},
+		{func() { Errorf("formatted %s", "error") }, "ERROR:\tformatted error\n"},
+		{func() { ErrorE(e, "request %d", 7) }, "ERROR:\trequest 7: boom\n"},
+		{func() { Errorw("failed", "code", 500) }, "ERROR:\tfailed code=500\n"},
+		{func() { l.Warning("logger") }, "WARNING:\tlogger\n"},
+		{func() { l.Infof("logger %d", 1) }, "INFO:\tlogger 1\n"},
+		{func() { l.ErrorE(e, "logger") }, "ERROR:\tlogger: boom\n"},
+		{func() { l.Debugw("logger", "k", "v") }, "DEBUG:\tlogger k=v\n"},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		tt.call()
+		if buf.String() != tt.want {
+			t.Errorf("got %q, want %q", buf.String(), tt.want)
+		}
+	}
+
+	buf.Reset()
+	InfoEnable(false)
+	Info("disabled")
+	Infof("disabled")
+	InfoE(e, "disabled")
+	Infow("disabled")
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
+
+func TestFatalf(t *testing.T) {
+	if os.Getenv("LW_TEST_FATAL") == "1" {
+		Enable(false, false, nil)
+		SetTimeFormat(TimeFormatNone)
+		Fatalf("This is a test %s with the number %d", "MESSAGE", 42)
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFatalf$")
+	cmd.Env = append(os.Environ(), "LW_TEST_FATAL=1")
+	out, err := cmd.Output()
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) || ee.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1, got %v", err)
+	}
+	if want := "FATAL:\tThis is a test MESSAGE with the number 42\n"; string(out) != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,81 @@
+package lw
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Recover recovers from a panic of the calling goroutine and writes it as
+// an Error log-entry.  The log-entry carries the panic value, its type in
+// field "type" and the stack of the panicking goroutine, and is attributed
+// to the location of the panic.  Like Fatal log-entries, panics are written
+// even when lw or the message type is disabled, so that they are never
+// lost silently.  Recover must be deferred directly.
+// Usage Example:
+// defer lw.Recover()
+func Recover() {
+	if v := recover(); v != nil {
+		logPanic(LevelError, v, false)
+	}
+}
+
+// RecoverAndLog recovers from a panic of the calling goroutine and writes
+// it as a log-entry of message type l in the manner of Recover.  If l is
+// LevelFatal the application is terminated.  Otherwise, if rethrow is set,
+// the panic is resumed once the log-entry and any pending summary
+// log-entries have been written.  RecoverAndLog must be deferred directly.
+// Usage Example:
+// defer lw.RecoverAndLog(lw.LevelError, true)
+func RecoverAndLog(l Level, rethrow bool) {
+	if v := recover(); v != nil {
+		logPanic(l, v, rethrow)
+	}
+}
+
+// Go runs f in a new goroutine, writing any panic of f as an Error
+// log-entry rather than terminating the application.  The panic is
+// written regardless of the lw settings, as per Recover.
+// Usage Example:
+// lw.Go(func() { worker(jobs) })
+func Go(f func()) {
+	go func() {
+		defer Recover()
+		f()
+	}()
+}
+
+// logPanic writes recovered panic value v as a log-entry of message type l
+// and resumes the panic if rethrow is set.  Panics are never disabled or
+// sampled.
+func logPanic(l Level, v interface{}, rethrow bool) {
+	d := panicDepth()
+	outputStack(d, std, l, "panic: "+fmt.Sprint(v), []interface{}{"type", fmt.Sprintf("%T", v)}, "", captureStack(d))
+	if rethrow {
+		Flush()
+		panic(v)
+	}
+}
+
+// panicDepth returns the depth of the location of the current panic, where
+// 1 identifies the caller of panicDepth.  The location is the first frame
+// outside of the runtime below runtime.gopanic.  If it cannot be
+// determined, the caller of Recover or RecoverAndLog is used.
+func panicDepth() int {
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	panicking := false
+	for d := 1; ; d++ {
+		fr, more := frames.Next()
+		if panicking && !strings.HasPrefix(fr.Function, "runtime.") {
+			return d
+		}
+		if fr.Function == "runtime.gopanic" {
+			panicking = true
+		}
+		if !more {
+			return 3
+		}
+	}
+}
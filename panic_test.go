@@ -0,0 +1,128 @@
+package lw
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// panicIndex panics with an index out of range error.
+func panicIndex(s []int) int {
+	return s[3]
+}
+
+func TestRecover(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:      true,
+		LocEnabled:   true,
+		ErrorEnabled: true,
+		TimeFormat:   TimeFormatNone,
+		LocMode:      LocShort,
+		StackTrim:    true,
+	}, &buf)
+
+	func() {
+		defer Recover()
+		panicIndex(nil)
+	}()
+	out := buf.String()
+	first := strings.SplitN(out, "\n", 2)[0]
+	want := "ERROR:\tpanic: runtime error: index out of range [3] with length 0 type=runtime.boundsError\tpanic_test.go line:15"
+	if first != want {
+		t.Errorf("got %q, want %q", first, want)
+	}
+	if !strings.Contains(out, "\tlw.panicIndex\tpanic_test.go line:15\n") || !strings.Contains(out, "\tlw.TestRecover.func1\t") {
+		t.Errorf("expected the stack of the panic, got %q", out)
+	}
+
+	// panics are written even when disabled
+	for _, disable := range []func(){func() { ErrorEnable(false) }, func() { Disable() }} {
+		buf.Reset()
+		disable()
+		func() {
+			defer Recover()
+			panic("disabled")
+		}()
+		if !strings.HasPrefix(buf.String(), "ERROR:\tpanic: disabled type=string") {
+			t.Errorf("expected the panic to be written, got %q", buf.String())
+		}
+	}
+}
+
+func TestRecoverAndLog(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{Enabled: true, WarningEnabled: true, TimeFormat: TimeFormatNone}, &buf)
+
+	var v interface{}
+	func() {
+		defer func() { v = recover() }()
+		defer RecoverAndLog(LevelWarning, true)
+		panic(strconv.ErrRange)
+	}()
+	if v != strconv.ErrRange {
+		t.Errorf("expected the panic to be resumed, got %v", v)
+	}
+	if !strings.HasPrefix(buf.String(), "WARNING:\tpanic: value out of range type=*errors.errorString\n") {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+// chanWriter passes a copy of each write to a channel.
+type chanWriter chan string
+
+func (w chanWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func TestGo(t *testing.T) {
+	defer DisableAndReset()
+	w := make(chanWriter, 1)
+	InitWithSettings(LogWriterState{Enabled: true, ErrorEnabled: true, TimeFormat: TimeFormatNone}, w)
+
+	Go(func() {
+		panic("worker failed")
+	})
+	select {
+	case out := <-w:
+		if !strings.HasPrefix(out, "ERROR:\tpanic: worker failed type=string\n") {
+			t.Errorf("unexpected output %q", out)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the panic to be logged")
+	}
+}
+
+func TestRecoverFatal(t *testing.T) {
+	if os.Getenv("LW_TEST_FATAL") == "1" {
+		InitWithSettings(LogWriterState{
+			Enabled:        true,
+			ErrorEnabled:   true,
+			TimeFormat:     TimeFormatNone,
+			SampleFirst:    1,
+			SampleInterval: time.Hour,
+		}, os.Stdout)
+		for i := 0; i < 2; i++ {
+			Errorf("repeated")
+		}
+		defer RecoverAndLog(LevelFatal, false)
+		panic("fatal")
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=TestRecoverFatal")
+	cmd.Env = append(os.Environ(), "LW_TEST_FATAL=1")
+	out, err := cmd.Output()
+	if e, ok := err.(*exec.ExitError); !ok || e.ExitCode() != 1 {
+		t.Fatalf("expected exit status 1, got %v", err)
+	}
+	s := string(out)
+	if !strings.HasPrefix(s, "ERROR:\trepeated\nFATAL:\tpanic: fatal type=string\n") || !strings.HasSuffix(s, "ERROR:\tsuppressed 1 messages like \"repeated\"\n") {
+		t.Errorf("unexpected output %q", s)
+	}
+}
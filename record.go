@@ -0,0 +1,91 @@
+package lw
+
+import (
+	"fmt"
+	"time"
+)
+
+// Record is a log-entry prior to being written.  Records are passed to
+// hooks, which may modify them before they are written.
+type Record struct {
+	Level   Level
+	Time    time.Time
+	Logger  string
+	Message string
+	Caller  Caller
+	Fields  []Field
+
+	// detail and stack hold the rendered causes of an error and the
+	// rendered stack, written on the lines following the log-entry.
+	detail string
+	stack  string
+}
+
+// Caller identifies the source location a log-entry was written from.
+// The zero value indicates that the location was not determined.
+type Caller struct {
+	File     string
+	Line     int
+	Function string
+
+	// pc is the program counter of the call, used to report the source
+	// location to slog.Handlers.
+	pc uintptr
+}
+
+// Field is a key/value pair written after the message of a log-entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// AddField appends field key=value to r.
+func (r *Record) AddField(key string, value interface{}) {
+	r.Fields = append(r.Fields, Field{Key: key, Value: value})
+}
+
+// appendFields appends the alternating keys and values in kv to dst.  A
+// trailing key without a value is appended with the key "!BADKEY".
+func appendFields(dst []Field, kv []interface{}) []Field {
+	for i := 0; i < len(kv); i += 2 {
+		if i+1 == len(kv) {
+			dst = append(dst, Field{Key: "!BADKEY", Value: kv[i]})
+			break
+		}
+		k, ok := kv[i].(string)
+		if !ok {
+			k = fmt.Sprint(kv[i])
+		}
+		dst = append(dst, Field{Key: k, Value: kv[i+1]})
+	}
+	return dst
+}
+
+// redactRecord applies the redaction configuration to the message, fields
+// and error detail of r.
+func redactRecord(r *Record) {
+	r.Message = redact(r.Message)
+	r.detail = redact(r.detail)
+	for i, f := range r.Fields {
+		if redactField(f.Key) {
+			r.Fields[i].Value = Redacted
+			continue
+		}
+		s := fmt.Sprint(f.Value)
+		if rs := redact(s); rs != s {
+			r.Fields[i].Value = rs
+		}
+	}
+}
+
+// appendText appends the first line of r to b, excluding the message type
+// label, timestamp and caller location.
+func (r *Record) appendText(b []byte) []byte {
+	if r.Logger != "" {
+		b = append(b, '[')
+		b = appendLines(b, r.Logger)
+		b = append(b, "] "...)
+	}
+	b = appendLines(b, r.Message)
+	return appendFieldsText(b, r.Fields)
+}
@@ -0,0 +1,191 @@
+package lw
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Redacted replaces sensitive data removed by redaction.
+const Redacted = "[REDACTED]"
+
+// Redactor removes sensitive data from the text of a log-entry.
+type Redactor interface {
+	Redact(s string) string
+}
+
+// RedactorFunc is an adapter allowing an ordinary function to be used as
+// a Redactor.
+type RedactorFunc func(s string) string
+
+// Redact calls f(s).
+func (f RedactorFunc) Redact(s string) string {
+	return f(s)
+}
+
+// regexpRedactor replaces the matches of re with repl, which may refer to
+// submatches in the manner of regexp.Regexp.ReplaceAllString.
+type regexpRedactor struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+func (r regexpRedactor) Redact(s string) string {
+	return r.re.ReplaceAllString(s, r.repl)
+}
+
+// Built-in Redactors.  All of them are active by default once redaction
+// has been enabled via RedactEnable.
+var (
+	// RedactAuthorization removes the credentials of Authorization headers
+	// and of bearer tokens.
+	RedactAuthorization Redactor = redactors{
+		regexpRedactor{regexp.MustCompile(`(?i)((?:proxy-)?authorization["']?\s*[:=]\s*\[?["']?)(?:(?:bearer|basic|digest|token)\s+)?[^\s"'\],]+`), "${1}" + Redacted},
+		regexpRedactor{regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`), "${1} " + Redacted},
+	}
+
+	// RedactJWT removes JSON Web Tokens.
+	RedactJWT Redactor = regexpRedactor{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), Redacted}
+
+	// RedactCreditCard removes sequences of 13 to 19 digits, optionally
+	// separated by spaces or dashes, that pass the Luhn check.
+	RedactCreditCard Redactor = RedactorFunc(func(s string) string {
+		return cardRe.ReplaceAllStringFunc(s, func(m string) string {
+			if luhn(m) {
+				return Redacted
+			}
+			return m
+		})
+	})
+
+	// RedactEmail removes e-mail addresses.
+	RedactEmail Redactor = regexpRedactor{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), Redacted}
+)
+
+var cardRe = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+// redactors applies a list of Redactors in order.
+type redactors []Redactor
+
+func (rs redactors) Redact(s string) string {
+	for _, r := range rs {
+		s = r.Redact(s)
+	}
+	return s
+}
+
+// redaction is an immutable redaction configuration.
+type redaction struct {
+	redactors redactors
+	fields    map[string]bool
+}
+
+var redactConfig atomic.Value
+
+func init() {
+	resetRedaction()
+}
+
+// RedactEnable enables/disables the redaction of log-entries.  When
+// enabled, the message, fields and error details of each log-entry are
+// passed through the configured Redactors before being written, and the
+// values of fields named in the field denylist are replaced.
+func RedactEnable(a bool) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.redactEnabled = a
+}
+
+// SetRedactors replaces the configured Redactors, including the built-in
+// ones, with r.
+// Usage Example:
+// lw.SetRedactors(lw.RedactAuthorization, lw.RedactJWT)
+func SetRedactors(r ...Redactor) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	c := *redactConfig.Load().(*redaction)
+	c.redactors = append(redactors(nil), r...)
+	redactConfig.Store(&c)
+}
+
+// AddRedactor adds r to the configured Redactors.
+func AddRedactor(r Redactor) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	c := *redactConfig.Load().(*redaction)
+	c.redactors = append(append(redactors(nil), c.redactors...), r)
+	redactConfig.Store(&c)
+}
+
+// AddRedactPattern adds a Redactor replacing all matches of regular
+// expression expr.
+// Usage Example:
+// err := lw.AddRedactPattern(`session=[0-9a-f]+`)
+func AddRedactPattern(expr string) error {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return err
+	}
+	AddRedactor(regexpRedactor{re: re, repl: Redacted})
+	return nil
+}
+
+// SetRedactFields sets the field denylist.  The values of fields whose
+// keys match one of names, ignoring case, are replaced.
+// Usage Example:
+// lw.SetRedactFields("password", "token", "ssn")
+func SetRedactFields(names ...string) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	c := *redactConfig.Load().(*redaction)
+	c.fields = make(map[string]bool, len(names))
+	for _, n := range names {
+		c.fields[strings.ToLower(n)] = true
+	}
+	redactConfig.Store(&c)
+}
+
+// resetRedaction restores the default redaction configuration.
+func resetRedaction() {
+	redactConfig.Store(&redaction{
+		redactors: redactors{RedactAuthorization, RedactJWT, RedactCreditCard, RedactEmail},
+	})
+}
+
+// redact passes s through the configured Redactors.
+func redact(s string) string {
+	if s == "" {
+		return s
+	}
+	return redactConfig.Load().(*redaction).redactors.Redact(s)
+}
+
+// redactField reports whether the value of field k must be replaced.
+func redactField(k string) bool {
+	if !logWriter.redactEnabled {
+		return false
+	}
+	f := redactConfig.Load().(*redaction).fields
+	return len(f) > 0 && f[strings.ToLower(k)]
+}
+
+// luhn reports whether the digits in s pass the Luhn checksum.
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
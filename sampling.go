@@ -0,0 +1,342 @@
+package lw
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxSampleKeys bounds the number of message keys tracked by the sampler.
+const maxSampleKeys = 4096
+
+// suppressed counts the log-entries dropped by sampling and rate limiting.
+var suppressed uint64
+
+// sampleKey identifies a family of log-entries for sampling purposes.
+type sampleKey struct {
+	l Level
+	s string
+}
+
+// sampleCount tracks the log-entries seen for a sampleKey in the current
+// interval.  lg is the Logger of the first log-entry dropped, which writes
+// the summary log-entry.
+type sampleCount struct {
+	start      time.Time
+	n          uint64
+	suppressed uint64
+	lg         *Logger
+}
+
+// sampler implements first-N-then-every-Mth sampling per sampleKey.
+type sampler struct {
+	mu     sync.Mutex
+	counts map[sampleKey]*sampleCount
+}
+
+var samples = sampler{counts: make(map[sampleKey]*sampleCount)}
+
+// SetSampling enables the sampling of log-entries.  In every interval the
+// first log-entries sharing a message type and format string are written,
+// after which only every thereafter-th log-entry is written.  A thereafter
+// value of 0 drops all log-entries after the first.  The number of
+// log-entries dropped in an interval is reported by a summary log-entry,
+// written ahead of the first log-entry sharing their message type and
+// format string after the interval has ended, or by Flush.  Use FlushEvery
+// to have the summaries written periodically regardless of further
+// logging.  A first value of 0 disables sampling.  An interval <= 0 selects
+// the default interval of one second.  Fatal log-entries are never sampled.
+// Usage Example:
+// lw.SetSampling(100, 1000, time.Second)
+func SetSampling(first, thereafter int, interval time.Duration) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.sampleFirst = first
+	logWriter.sampleEvery = thereafter
+	logWriter.sampleInterval = interval
+	samples.reset()
+}
+
+// Suppressed returns the total number of log-entries dropped by sampling
+// and rate limiting.
+func Suppressed() uint64 {
+	return atomic.LoadUint64(&suppressed)
+}
+
+// FlushSuppressed writes the summary log-entries for messages dropped by
+// sampling in the current interval and by rate limited Loggers, rather
+// than waiting for further log-entries to be written.  Call it when
+// shutting down, or use FlushEvery if messages are dropped in bursts.
+func FlushSuppressed() {
+	type report struct {
+		lg *Logger
+		k  sampleKey
+		n  uint64
+	}
+	var reports []report
+	samples.mu.Lock()
+	for k, c := range samples.counts {
+		if c.suppressed > 0 {
+			reports = append(reports, report{c.lg, k, c.suppressed})
+			c.suppressed = 0
+		}
+	}
+	samples.mu.Unlock()
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].k.l != reports[j].k.l {
+			return reports[i].k.l < reports[j].k.l
+		}
+		return reports[i].k.s < reports[j].k.s
+	})
+	for _, r := range limiters.drain() {
+		n, like := r.flush()
+		if n > 0 {
+			reports = append(reports, report{r.lg, like, n})
+		}
+	}
+	for _, r := range reports {
+		if enabled(r.lg, r.k.l) {
+			output(2, r.lg, r.k.l, suppressedText(r.n, r.k.s), nil, "")
+		}
+	}
+}
+
+// FlushEvery calls Flush every d until the returned function is called,
+// so that summary log-entries are written periodically even when no
+// further log-entries are written.  A d <= 0 starts nothing, and the
+// returned function does nothing.
+// Usage Example:
+// defer lw.FlushEvery(10 * time.Second)()
+func FlushEvery(d time.Duration) func() {
+	if d <= 0 {
+		return func() {}
+	}
+	t := time.NewTicker(d)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-t.C:
+				Flush()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		t.Stop()
+		close(done)
+		<-stopped
+	}
+}
+
+// reset discards all sampling state.
+func (sm *sampler) reset() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.counts = make(map[sampleKey]*sampleCount)
+}
+
+// sample reports whether the log-entry identified by k, written by Logger
+// lg, should be written.  If the interval of k has ended, the number of
+// log-entries dropped in that interval is returned along with the Logger
+// to report them through.
+func (sm *sampler) sample(k sampleKey, lg *Logger) (bool, uint64, *Logger) {
+	first := uint64(logWriter.sampleFirst)
+	thereafter := uint64(logWriter.sampleEvery)
+	interval := logWriter.sampleInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	t := now()
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	var report uint64
+	var rlg *Logger
+	c, ok := sm.counts[k]
+	if !ok {
+		if len(sm.counts) >= maxSampleKeys {
+			sm.sweep(t, interval)
+		}
+		c = &sampleCount{start: t}
+		sm.counts[k] = c
+	} else if t.Sub(c.start) >= interval {
+		report, rlg = c.suppressed, c.lg
+		*c = sampleCount{start: t}
+	}
+	c.n++
+	if c.n <= first || (thereafter > 0 && (c.n-first)%thereafter == 0) {
+		return true, report, rlg
+	}
+	if c.suppressed == 0 {
+		c.lg = lg
+	}
+	c.suppressed++
+	atomic.AddUint64(&suppressed, 1)
+	return false, report, rlg
+}
+
+// sweep discards the keys whose interval has ended without dropping a
+// log-entry.  If the sampler is still full, all keys are discarded.
+func (sm *sampler) sweep(t time.Time, interval time.Duration) {
+	for k, c := range sm.counts {
+		if c.suppressed == 0 && t.Sub(c.start) >= interval {
+			delete(sm.counts, k)
+		}
+	}
+	if len(sm.counts) >= maxSampleKeys {
+		sm.counts = make(map[sampleKey]*sampleCount)
+	}
+}
+
+// rateLimiter is a token bucket shared by a Logger and the Loggers
+// derived from it.  lg is the Logger the limit was set on, which writes
+// the summary log-entries on Flush.
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	dropped uint64
+	like    sampleKey
+	lg      *Logger
+}
+
+// limiterSet tracks the rate limiters holding unreported drops, so that
+// Flush can report them.
+type limiterSet struct {
+	mu sync.Mutex
+	m  map[*rateLimiter]struct{}
+}
+
+var limiters = limiterSet{m: make(map[*rateLimiter]struct{})}
+
+// add adds r to the set.
+func (ls *limiterSet) add(r *rateLimiter) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.m[r] = struct{}{}
+}
+
+// remove removes r from the set.
+func (ls *limiterSet) remove(r *rateLimiter) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	delete(ls.m, r)
+}
+
+// drain empties the set and returns its former members.
+func (ls *limiterSet) drain() []*rateLimiter {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	rs := make([]*rateLimiter, 0, len(ls.m))
+	for r := range ls.m {
+		rs = append(rs, r)
+	}
+	ls.m = make(map[*rateLimiter]struct{})
+	return rs
+}
+
+// WithRateLimit returns a Logger that writes at most perSecond log-entries
+// per second on average, allowing bursts of up to burst log-entries.  The
+// limit is shared by all Loggers derived from the returned Logger.  The
+// number of log-entries dropped is reported by a summary log-entry ahead
+// of the next log-entry written once the limit allows, or by Flush.
+// Fatal log-entries are never dropped.
+// Usage Example:
+// pollLog := lw.Named("poller").WithRateLimit(10, 100)
+func WithRateLimit(perSecond float64, burst int) *Logger {
+	return std.WithRateLimit(perSecond, burst)
+}
+
+// WithRateLimit returns a copy of l limited to perSecond log-entries per
+// second with bursts of up to burst log-entries.  See the package-level
+// WithRateLimit function.
+func (l *Logger) WithRateLimit(perSecond float64, burst int) *Logger {
+	c := *l
+	c.limiter = &rateLimiter{rate: perSecond, burst: float64(burst), tokens: float64(burst), last: now(), lg: &c}
+	return &c
+}
+
+// allow reports whether a log-entry identified by k may be written.  When a
+// log-entry is allowed after others were dropped, the number dropped and
+// the key of the first dropped log-entry are returned.
+func (r *rateLimiter) allow(k sampleKey) (bool, uint64, sampleKey) {
+	t := now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens += t.Sub(r.last).Seconds() * r.rate
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.last = t
+	if r.tokens < 1 {
+		if r.dropped == 0 {
+			r.like = k
+			limiters.add(r)
+		}
+		r.dropped++
+		atomic.AddUint64(&suppressed, 1)
+		return false, 0, k
+	}
+	r.tokens--
+	n, like := r.dropped, r.like
+	if n > 0 {
+		r.dropped = 0
+		limiters.remove(r)
+	}
+	return true, n, like
+}
+
+// flush returns the number of log-entries dropped since the last report
+// and the key of the first of them, and resets the count.
+func (r *rateLimiter) flush() (uint64, sampleKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.dropped
+	r.dropped = 0
+	return n, r.like
+}
+
+// admit applies sampling and rate limiting to a log-entry of message type
+// l with format string or message s, and writes any pending summary
+// log-entries.  It reports whether the log-entry should be written.
+func admit(calldepth int, lg *Logger, l Level, s string) bool {
+	if l == LevelFatal {
+		return true
+	}
+	k := sampleKey{l: l, s: s}
+	if logWriter.sampleFirst > 0 {
+		ok, n, rlg := samples.sample(k, lg)
+		if n > 0 && enabled(rlg, l) {
+			// the summary is attributed to the caller of lg
+			rl := *rlg
+			rl.skip = lg.skip
+			output(calldepth+1, &rl, l, suppressedText(n, s), nil, "")
+		}
+		if !ok {
+			return false
+		}
+	}
+	if lg.limiter != nil {
+		ok, n, like := lg.limiter.allow(k)
+		if n > 0 && enabled(lg, like.l) {
+			output(calldepth+1, lg, like.l, suppressedText(n, like.s), nil, "")
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// suppressedText returns the message of a summary log-entry.
+func suppressedText(n uint64, s string) string {
+	return "suppressed " + strconv.FormatUint(n, 10) + " messages like " + strconv.Quote(s)
+}
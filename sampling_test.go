@@ -0,0 +1,162 @@
+package lw
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSampling(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	clock := time.Unix(0, 0)
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		WarningEnabled: true,
+		TimeFormat:     TimeFormatNone,
+		Clock:          func() time.Time { return clock },
+		SampleFirst:    2,
+		SampleEvery:    3,
+		SampleInterval: time.Second,
+	}, &buf)
+
+	before := Suppressed()
+	for i := 1; i <= 8; i++ {
+		Warningf("hot loop %d", i)
+		Warningf("other")
+	}
+	want := "WARNING:\thot loop 1\nWARNING:\tother\n" +
+		"WARNING:\thot loop 2\nWARNING:\tother\n" +
+		"WARNING:\thot loop 5\nWARNING:\tother\n" +
+		"WARNING:\thot loop 8\nWARNING:\tother\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if n := Suppressed() - before; n != 8 {
+		t.Errorf("expected 8 suppressed messages, got %d", n)
+	}
+
+	// the first message of the next interval reports the dropped messages
+	buf.Reset()
+	clock = clock.Add(time.Second)
+	Warningf("hot loop %d", 9)
+	want = "WARNING:\tsuppressed 4 messages like \"hot loop %d\"\nWARNING:\thot loop 9\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	FlushSuppressed()
+	if want := "WARNING:\tsuppressed 4 messages like \"other\"\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSamplingLogger(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	clock := time.Unix(0, 0)
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		TimeFormat:     TimeFormatNone,
+		Clock:          func() time.Time { return clock },
+		Levels:         "db=debug",
+		SampleFirst:    1,
+		SampleInterval: time.Second,
+	}, &buf)
+
+	db := Named("db")
+	for i := 0; i < 3; i++ {
+		db.Debugf("query")
+	}
+	buf.Reset()
+	Flush()
+	if want := "DEBUG:\t[db] suppressed 2 messages like \"query\"\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	db.Debugf("query")
+	buf.Reset()
+	clock = clock.Add(time.Second)
+	db.Debugf("query")
+	if want := "DEBUG:\t[db] suppressed 1 messages like \"query\"\nDEBUG:\t[db] query\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	clock := time.Unix(0, 0)
+	Enable(false, false, &buf)
+	InfoEnable(true)
+	SetTimeFormat(TimeFormatNone)
+	SetClock(func() time.Time { return clock })
+
+	l := Named("poller").WithRateLimit(2, 3)
+	child := l.With("id", 1)
+	for i := 0; i < 5; i++ {
+		l.Info("poll")
+	}
+	child.Info("child")
+	if n := strings.Count(buf.String(), "\n"); n != 3 {
+		t.Errorf("expected a burst of 3, got %q", buf.String())
+	}
+
+	buf.Reset()
+	clock = clock.Add(500 * time.Millisecond)
+	l.Info("poll")
+	want := "INFO:\t[poller] suppressed 3 messages like \"poll\"\nINFO:\t[poller] poll\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// other Loggers are not limited
+	buf.Reset()
+	for i := 0; i < 5; i++ {
+		Info("unlimited")
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 5 {
+		t.Errorf("expected 5 log-entries, got %q", buf.String())
+	}
+
+	// Flush reports the drops of a Logger that has gone quiet
+	buf.Reset()
+	for i := 0; i < 5; i++ {
+		child.Info("poll")
+	}
+	Flush()
+	Flush()
+	if want := "INFO:\t[poller] suppressed 5 messages like \"poll\"\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFlushEvery(t *testing.T) {
+	defer DisableAndReset()
+	w := make(chanWriter, 4)
+	InitWithSettings(LogWriterState{
+		Enabled:        true,
+		WarningEnabled: true,
+		TimeFormat:     TimeFormatNone,
+		SampleFirst:    1,
+		SampleInterval: time.Hour,
+	}, w)
+
+	Warningf("hot loop")
+	Warningf("hot loop")
+	FlushEvery(0)()
+	stop := FlushEvery(time.Millisecond)
+	defer stop()
+	for _, want := range []string{"WARNING:\thot loop\n", "WARNING:\tsuppressed 1 messages like \"hot loop\"\n"} {
+		select {
+		case got := <-w:
+			if got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+}
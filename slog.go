@@ -0,0 +1,209 @@
+package lw
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// slogLevel returns the slog level corresponding to lw message type l.
+func slogLevel(l Level) slog.Level {
+	switch l {
+	case LevelTrace:
+		return slog.LevelDebug - 4
+	case LevelDebug:
+		return slog.LevelDebug
+	case LevelInfo:
+		return slog.LevelInfo
+	case LevelWarning:
+		return slog.LevelWarn
+	case LevelError:
+		return slog.LevelError
+	}
+	return slog.LevelError + 4
+}
+
+// levelOfSlog returns the lw message type corresponding to slog level l.
+// Levels below slog.LevelDebug map to Trace.  There is no slog level
+// mapping to Fatal, so slog records never terminate the application.
+func levelOfSlog(l slog.Level) Level {
+	switch {
+	case l < slog.LevelDebug:
+		return LevelTrace
+	case l < slog.LevelInfo:
+		return LevelDebug
+	case l < slog.LevelWarn:
+		return LevelInfo
+	case l < slog.LevelError:
+		return LevelWarning
+	}
+	return LevelError
+}
+
+// slogHandler is a slog.Handler writing slog records as lw log-entries.
+type slogHandler struct {
+	lg     *Logger
+	prefix string
+}
+
+// NewSlogHandler returns a slog.Handler writing slog records as lw
+// log-entries via the package-level configuration, so that new code using
+// log/slog shares the output, activation settings and hooks of lw.  slog
+// levels are mapped to the nearest lw message type, attributes are
+// written as fields, with keys qualified by their groups in dotted form,
+// and the source location is taken from the slog record.
+// Usage Example:
+// slog.SetDefault(slog.New(lw.NewSlogHandler()))
+func NewSlogHandler() slog.Handler {
+	return std.SlogHandler()
+}
+
+// SlogHandler returns a slog.Handler writing slog records as log-entries
+// of l.  See NewSlogHandler.
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{lg: l}
+}
+
+// Enabled reports whether lw writes log-entries of the message type
+// corresponding to level l.
+func (h *slogHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return enabled(h.lg, levelOfSlog(l))
+}
+
+// Handle writes slog record sr as a log-entry.  The request ID, trace ID
+// and fields carried by ctx are written ahead of the attributes of sr.  A
+// record with a zero time is written without a timestamp.
+func (h *slogHandler) Handle(ctx context.Context, sr slog.Record) error {
+	l := levelOfSlog(sr.Level)
+	if !enabled(h.lg, l) || !admit(1, h.lg, l, sr.Message) {
+		return nil
+	}
+	e := getEncoder()
+	r := Record{Level: l, Time: sr.Time, Logger: h.lg.name, Message: sr.Message}
+	e.fields = appendFields(appendFields(e.fields, h.lg.fields), contextFields(ctx))
+	sr.Attrs(func(a slog.Attr) bool {
+		e.fields = appendAttr(e.fields, h.prefix, a)
+		return true
+	})
+	resolveLazy(e.fields)
+	if len(e.fields) > 0 {
+		r.Fields = e.fields
+	}
+	hs := hooks.Load().([]hook)
+	if sr.PC != 0 && needCaller(hs) {
+		fr, _ := runtime.CallersFrames([]uintptr{sr.PC}).Next()
+		r.Caller = Caller{File: fr.File, Line: fr.Line, Function: fr.Function, pc: sr.PC}
+	}
+	emit(e, hs, &r)
+	return nil
+}
+
+// WithAttrs returns a slog.Handler writing attrs with each log-entry in
+// addition to the attributes of h.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	var f []Field
+	for _, a := range attrs {
+		f = appendAttr(f, h.prefix, a)
+	}
+	kv := make([]interface{}, 0, 2*len(f))
+	for _, fl := range f {
+		kv = append(kv, fl.Key, fl.Value)
+	}
+	return &slogHandler{lg: h.lg.With(kv...), prefix: h.prefix}
+}
+
+// WithGroup returns a slog.Handler qualifying the keys of all subsequent
+// attributes with group name.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{lg: h.lg, prefix: h.prefix + name + "."}
+}
+
+// appendAttr appends attribute a to dst as a field whose key is qualified
+// by prefix.  Group attributes are flattened, and empty attributes and
+// groups are omitted as required by slog.Handler.
+func appendAttr(dst []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return dst
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			dst = appendAttr(dst, prefix, ga)
+		}
+		return dst
+	}
+	return append(dst, Field{Key: prefix + a.Key, Value: a.Value.Any()})
+}
+
+// slogHook is a Hook forwarding Records to a slog.Handler.
+type slogHook struct {
+	h slog.Handler
+}
+
+// NewSlogHook returns a Hook sending each Record to slog.Handler h rather
+// than writing it via lw.  Records are converted to slog records carrying
+// the name of the Logger as attribute "logger" followed by the fields of
+// the Record.  The causes of an error written with error detail enabled
+// are passed as attribute "causes" and a captured stack as attribute
+// "stack", each as a []string holding one cause or frame per element.
+// Fatal Records are sent at level slog.LevelError+4 before the application
+// is terminated.  If h fails to handle a Record, the Record is written via
+// lw instead, with the error in field "slog_error".  Register the hook
+// last, as it prevents the Records it receives from being passed to
+// subsequent hooks.
+// Usage Example:
+// lw.AddHook(lw.LevelTrace, lw.NewSlogHook(slog.NewJSONHandler(os.Stderr, nil)))
+func NewSlogHook(h slog.Handler) Hook {
+	return slogHook{h: h}
+}
+
+// Run sends Record r to the slog.Handler and vetoes writing it via lw.
+func (s slogHook) Run(r Record) (Record, bool) {
+	ctx := context.Background()
+	l := slogLevel(r.Level)
+	if !s.h.Enabled(ctx, l) {
+		return r, false
+	}
+	sr := slog.NewRecord(r.Time, l, r.Message, r.Caller.pc)
+	if r.Logger != "" {
+		sr.AddAttrs(slog.String("logger", r.Logger))
+	}
+	for _, f := range r.Fields {
+		sr.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	if r.detail != "" {
+		sr.AddAttrs(slog.Any("causes", splitLines(r.detail, "cause: ")))
+	}
+	if r.stack != "" {
+		sr.AddAttrs(slog.Any("stack", splitLines(r.stack, "")))
+	}
+	if err := s.h.Handle(ctx, sr); err != nil {
+		r.AddField("slog_error", err)
+		return r, true
+	}
+	return r, false
+}
+
+// splitLines splits the rendered causes or stack s into its lines,
+// removing the leading tab, the label and the trailing newline of each.
+// Tabs separating the function and location of a frame, or indenting a
+// cause, are kept.
+func splitLines(s, label string) []string {
+	ls := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, l := range ls {
+		l = strings.TrimPrefix(l, "\t")
+		t := strings.TrimLeft(l, "\t")
+		ls[i] = l[:len(l)-len(t)] + strings.TrimPrefix(t, label)
+	}
+	return ls
+}
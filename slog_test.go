@@ -0,0 +1,155 @@
+package lw
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/slogtest"
+)
+
+func TestSlogHandler(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:     true,
+		LocEnabled:  true,
+		InfoEnabled: true,
+		TimeFormat:  TimeFormatNone,
+		LocMode:     LocShort,
+	}, &buf)
+
+	sl := slog.New(Named("api").SlogHandler())
+	ctx := ContextWithRequestID(context.Background(), "r-1")
+	_, line := nextLine()
+	sl.With("version", 2).WithGroup("req").InfoContext(ctx, "served", "path", "/users", slog.Group("resp", "status", 200))
+	sl.Debug("dropped")
+
+	want := "INFO:\t[api] served version=2 request_id=r-1 req.path=/users req.resp.status=200\tslog_test.go line:" + strconv.Itoa(line) + "\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if !sl.Enabled(ctx, slog.LevelInfo) || sl.Enabled(ctx, slog.LevelDebug) {
+		t.Error("expected Enabled to follow the lw activation settings")
+	}
+}
+
+// TestSlogHandlerConformance checks the handler against the requirements
+// of log/slog, using a hook to capture the written Records.
+func TestSlogHandlerConformance(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{Enabled: true, InfoEnabled: true}, &buf)
+	var results []map[string]interface{}
+	AddHook(LevelTrace, HookFunc(func(r Record) (Record, bool) {
+		m := map[string]interface{}{
+			slog.LevelKey:   slogLevel(r.Level),
+			slog.MessageKey: r.Message,
+		}
+		if !r.Time.IsZero() {
+			m[slog.TimeKey] = r.Time
+		}
+		for _, f := range r.Fields {
+			keys := strings.Split(f.Key, ".")
+			g := m
+			for _, k := range keys[:len(keys)-1] {
+				sub, ok := g[k].(map[string]interface{})
+				if !ok {
+					sub = map[string]interface{}{}
+					g[k] = sub
+				}
+				g = sub
+			}
+			g[keys[len(keys)-1]] = f.Value
+		}
+		results = append(results, m)
+		return r, true
+	}))
+
+	err := slogtest.TestHandler(NewSlogHandler(), func() []map[string]interface{} {
+		return results
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSlogHook(t *testing.T) {
+	defer DisableAndReset()
+	var buf, sbuf bytes.Buffer
+	InitWithSettings(LogWriterState{Enabled: true, TraceEnabled: true, WarningEnabled: true}, &buf)
+	AddHook(LevelTrace, NewSlogHook(slog.NewTextHandler(&sbuf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})))
+
+	Named("db").Warningw("slow query", "ms", 1500)
+	Trace("below the handler level")
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no lw output, got %q", buf.String())
+	}
+	want := "level=WARN msg=\"slow query\" logger=db ms=1500\n"
+	if sbuf.String() != want {
+		t.Errorf("got %q, want %q", sbuf.String(), want)
+	}
+}
+
+// failingHandler is a slog.Handler failing to handle any record.
+type failingHandler struct {
+	slog.Handler
+}
+
+func (failingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (failingHandler) Handle(context.Context, slog.Record) error {
+	return errors.New("unavailable")
+}
+
+func TestSlogHookDetail(t *testing.T) {
+	defer DisableAndReset()
+	var buf, sbuf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:      true,
+		ErrorEnabled: true,
+		TimeFormat:   TimeFormatNone,
+		ErrorDetail:  true,
+		StackEnabled: true,
+		StackTrim:    true,
+		LocMode:      LocShort,
+	}, &buf)
+	AddHook(LevelTrace, NewSlogHook(slog.NewJSONHandler(&sbuf, nil)))
+
+	Error(fmt.Errorf("x: %w", errors.New("y")))
+	var m struct {
+		Msg    string
+		Causes []string
+		Stack  []string
+	}
+	if err := json.Unmarshal(sbuf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Msg != "x: y type=*fmt.wrapError" || len(m.Causes) != 1 || m.Causes[0] != "y type=*errors.errorString" {
+		t.Errorf("unexpected message or causes in %q", sbuf.String())
+	}
+	if len(m.Stack) == 0 || !strings.HasPrefix(m.Stack[0], "lw.TestSlogHookDetail\tslog_test.go line:") {
+		t.Errorf("unexpected stack in %q", sbuf.String())
+	}
+
+	ResetHooks()
+	AddHook(LevelTrace, NewSlogHook(failingHandler{}))
+	Errorf("undeliverable")
+	if !strings.HasPrefix(buf.String(), "ERROR:\tundeliverable slog_error=unavailable\n") {
+		t.Errorf("expected the Record to be written via lw, got %q", buf.String())
+	}
+}
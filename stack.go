@@ -0,0 +1,79 @@
+package lw
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// defaultStackDepth is the maximum number of frames captured when no
+// depth has been set via SetStackDepth.
+const defaultStackDepth = 32
+
+// StackEnable enables/disables the capture of the goroutine stack for
+// Error, ErrorWithPrefixString and Fatal log-entries.  The stack is written
+// on the lines following the log-entry, one frame per line.
+func StackEnable(a bool) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.stackEnabled = a
+}
+
+// SetStackDepth sets the maximum number of frames captured in a stack
+// trace.  A value <= 0 selects the default depth of 32 frames.
+func SetStackDepth(n int) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.stackDepth = n
+}
+
+// StackTrimEnable enables/disables the removal of frames belonging to the
+// runtime and testing packages from captured stack traces.
+func StackTrimEnable(a bool) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.stackTrim = a
+}
+
+// stack returns the rendered stack of the calling goroutine, or an empty
+// string if stack capture is disabled.  depth is the number of frames to
+// skip, where 1 identifies the caller of stack.  Each frame is written on
+// its own line as "\t<function>\t<file> line:<n>".
+func stack(depth int) string {
+	if !logWriter.stackEnabled {
+		return ""
+	}
+	return captureStack(depth + 1)
+}
+
+// captureStack returns the rendered stack of the calling goroutine
+// regardless of whether stack capture is enabled.  See stack.
+func captureStack(depth int) string {
+	max := logWriter.stackDepth
+	if max <= 0 {
+		max = defaultStackDepth
+	}
+	pcs := make([]uintptr, max)
+	n := runtime.Callers(depth+1+logWriter.callerSkip, pcs)
+	if n == 0 {
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		fr, more := frames.Next()
+		if !logWriter.stackTrim || !isRuntimeFrame(fr.Function) {
+			b.WriteString("\t" + funcName(fr.Function) + "\t" + trimPath(fr.File) + " line:" + strconv.Itoa(fr.Line) + "\n")
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// isRuntimeFrame reports whether function fn belongs to the runtime or
+// testing packages.
+func isRuntimeFrame(fn string) bool {
+	return strings.HasPrefix(fn, "runtime.") || strings.HasPrefix(fn, "testing.")
+}
@@ -0,0 +1,57 @@
+package lw
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:      true,
+		ErrorEnabled: true,
+		TimeFormat:   TimeFormatNone,
+		LocMode:      LocShort,
+		StackEnabled: true,
+		StackTrim:    true,
+	}, &buf)
+
+	_, line := nextLine()
+	Error(errors.New("boom"))
+	want := "ERROR:\tboom\n" +
+		"\tlw.TestStack\tstack_test.go line:" + strconv.Itoa(line) + "\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	// without trimming the testing and runtime frames follow
+	buf.Reset()
+	StackTrimEnable(false)
+	ErrorWithPrefixString("prefix", errors.New("boom"))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) < 3 || lines[0] != "ERROR:\tprefix boom" {
+		t.Fatalf("unexpected stack %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[2], "\ttesting.tRunner\t") {
+		t.Errorf("expected testing frame, got %q", lines[2])
+	}
+
+	buf.Reset()
+	SetStackDepth(1)
+	Error(errors.New("boom"))
+	if n := strings.Count(buf.String(), "\n"); n != 2 {
+		t.Errorf("expected 1 frame, got %q", buf.String())
+	}
+
+	// other message types never carry a stack
+	buf.Reset()
+	InfoEnable(true)
+	Info("no stack")
+	if want := "INFO:\tno stack\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
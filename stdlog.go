@@ -0,0 +1,120 @@
+package lw
+
+import (
+	"log"
+	"runtime"
+	"strings"
+)
+
+// stdWriter is an io.Writer routing the output of standard library
+// log.Logger log into lw.  The prefix and flags of log are read on
+// each write, as they may be changed at any time.
+type stdWriter struct {
+	lg  *Logger
+	l   Level
+	log *log.Logger
+}
+
+// NewStdLogger returns a standard library *log.Logger whose output is
+// written by lw as log-entries of message type l.  Use it to hand a
+// logger to dependencies that expect a *log.Logger.  See
+// RedirectStdLog for the handling of the stdlib prefix and flags.
+// Usage Example:
+// srv := &http.Server{ErrorLog: lw.NewStdLogger(lw.LevelError)}
+func NewStdLogger(l Level) *log.Logger {
+	return std.NewStdLogger(l)
+}
+
+// RedirectStdLog routes the output of the standard library log package,
+// as used by log.Print and friends, into lw as log-entries of message
+// type l.  The prefix of the standard logger and the date, time and
+// file:line written as per its current flags are stripped from each
+// message, and the caller location is that of the log.Print call.
+// Messages logged by log.Fatal and log.Panic still terminate the program
+// as documented by the log package.  The returned function restores the
+// previous output of the standard logger.
+// Usage Example:
+// defer lw.RedirectStdLog(lw.LevelInfo)()
+func RedirectStdLog(l Level) func() {
+	return std.RedirectStdLog(l)
+}
+
+// NewStdLogger returns a standard library *log.Logger whose output is
+// written by l as log-entries of message type lv.  See the package-level
+// NewStdLogger function.
+func (l *Logger) NewStdLogger(lv Level) *log.Logger {
+	w := &stdWriter{lg: l, l: lv}
+	w.log = log.New(w, "", 0)
+	return w.log
+}
+
+// RedirectStdLog routes the output of the standard library log package
+// into l as log-entries of message type lv.  See the package-level
+// RedirectStdLog function.
+func (l *Logger) RedirectStdLog(lv Level) func() {
+	prev := log.Writer()
+	log.SetOutput(&stdWriter{lg: l, l: lv, log: log.Default()})
+	return func() {
+		log.SetOutput(prev)
+	}
+}
+
+// Write writes the message in p as a log-entry, stripping the prefix,
+// header and trailing newline added by the log package.
+func (w *stdWriter) Write(p []byte) (int, error) {
+	if !enabled(w.lg, w.l) {
+		return len(p), nil
+	}
+	flags, prefix := w.log.Flags(), w.log.Prefix()
+	m := strings.TrimSuffix(string(p), "\n")
+	if flags&log.Lmsgprefix == 0 {
+		m = strings.TrimPrefix(m, prefix)
+	}
+	m = stripStdHeader(m, flags)
+	if flags&log.Lmsgprefix != 0 {
+		m = strings.TrimPrefix(m, prefix)
+	}
+	logp(stdCallDepth(), w.lg, w.l, m)
+	return len(p), nil
+}
+
+// stripStdHeader removes the date, time and file:line written by the log
+// package as per flags from the start of m.
+func stripStdHeader(m string, flags int) string {
+	n := 0
+	if flags&log.Ldate != 0 {
+		n += len("2006/01/02 ")
+	}
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		n += len("15:04:05 ")
+		if flags&log.Lmicroseconds != 0 {
+			n += len(".000000")
+		}
+	}
+	if n > len(m) {
+		return m
+	}
+	m = m[n:]
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		if i := strings.Index(m, ": "); i >= 0 {
+			m = m[i+2:]
+		}
+	}
+	return m
+}
+
+// stdCallDepth returns the call depth of the caller of the log package,
+// as seen from stdWriter.Write.
+func stdCallDepth() int {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	d := 2
+	for {
+		fr, more := frames.Next()
+		if !strings.HasPrefix(fr.Function, "log.") || !more {
+			return d
+		}
+		d++
+	}
+}
@@ -0,0 +1,88 @@
+package lw
+
+import (
+	"bytes"
+	"log"
+	"strconv"
+	"testing"
+)
+
+func TestNewStdLogger(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{
+		Enabled:      true,
+		LocEnabled:   true,
+		ErrorEnabled: true,
+		TimeFormat:   TimeFormatNone,
+		LocMode:      LocShort,
+	}, &buf)
+
+	sl := Named("http").NewStdLogger(LevelError)
+	_, line := nextLine()
+	sl.Printf("TLS handshake error from %s", "10.0.0.1")
+	want := "ERROR:\t[http] TLS handshake error from 10.0.0.1\tstdlog_test.go line:" + strconv.Itoa(line) + "\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	LocEnable(false)
+	for _, m := range []string{"2024/01/02 backup finished", "12:00:00 job started", "main.go:12: odd"} {
+		buf.Reset()
+		NewStdLogger(LevelError).Print(m)
+		if want := "ERROR:\t" + m + "\n"; buf.String() != want {
+			t.Errorf("got %q, want %q", buf.String(), want)
+		}
+	}
+
+	buf.Reset()
+	sl = NewStdLogger(LevelError)
+	sl.SetFlags(log.LstdFlags | log.Lshortfile)
+	sl.SetPrefix("db: ")
+	sl.Print("retry: 12:00:00")
+	if want := "ERROR:\tretry: 12:00:00\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	NewStdLogger(LevelInfo).Print("dropped")
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for a disabled message type, got %q", buf.String())
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	InitWithSettings(LogWriterState{Enabled: true, WarningEnabled: true, TimeFormat: TimeFormatNone}, &buf)
+
+	flags, prefix := log.Flags(), log.Prefix()
+	defer log.SetFlags(flags)
+	defer log.SetPrefix(prefix)
+
+	tests := []struct {
+		flags  int
+		prefix string
+	}{
+		{0, ""},
+		{log.LstdFlags, ""},
+		{log.LstdFlags | log.Lmicroseconds | log.Lshortfile, "app: "},
+		{log.Ldate | log.Llongfile | log.LUTC, "app: "},
+		{log.LstdFlags | log.Lmsgprefix, "app: "},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		log.SetFlags(tt.flags)
+		log.SetPrefix(tt.prefix)
+		restore := RedirectStdLog(LevelWarning)
+		log.Printf("2024/01/02 disk %d%% full", 91)
+		restore()
+		if want := "WARNING:\t2024/01/02 disk 91% full\n"; buf.String() != want {
+			t.Errorf("flags %d, prefix %q: got %q, want %q", tt.flags, tt.prefix, buf.String(), want)
+		}
+	}
+
+	if _, ok := log.Writer().(*stdWriter); ok {
+		t.Error("expected the previous output to be restored")
+	}
+}
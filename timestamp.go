@@ -0,0 +1,79 @@
+package lw
+
+import (
+	"strconv"
+	"time"
+)
+
+// Timestamp layouts understood by SetTimeFormat and LogWriterState.TimeFormat.
+// Any other non-empty value is treated as a custom time.Format layout.  An
+// empty value selects the default of TimeFormatRFC3339Nano.
+const (
+	TimeFormatRFC3339     = time.RFC3339
+	TimeFormatRFC3339Nano = time.RFC3339Nano
+	TimeFormatUnix        = "unix"
+	TimeFormatUnixMilli   = "unixmilli"
+	TimeFormatNone        = "none"
+)
+
+// SetTimeFormat sets the layout used to render the timestamp of each
+// log-entry.  Use one of the TimeFormat* constants or any layout accepted
+// by time.Format.  TimeFormatNone removes the timestamp from the output.
+func SetTimeFormat(f string) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.timeFormat = f
+}
+
+// SetUTC forces timestamps to be rendered in UTC rather than local time.
+func SetUTC(u bool) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.utc = u
+}
+
+// SetClock replaces the clock used to obtain the time of each log-entry.
+// This is mostly useful for deterministic tests.  Passing nil restores
+// the default of time.Now.
+func SetClock(c func() time.Time) {
+	logWriter.mu.Lock()
+	defer logWriter.mu.Unlock()
+	logWriter.clock = c
+}
+
+// now returns the current time as seen by the configured clock.
+func now() time.Time {
+	if logWriter.clock != nil {
+		return logWriter.clock()
+	}
+	return time.Now()
+}
+
+// timestamp returns time t of a log-entry formatted as per the current
+// settings, including the trailing separator.  An empty string is returned
+// for TimeFormatNone.
+func timestamp(t time.Time) string {
+	return string(appendTimestamp(nil, t))
+}
+
+// appendTimestamp appends the formatted time t, including the trailing
+// separator, to b.  Nothing is appended for the zero time.
+func appendTimestamp(b []byte, t time.Time) []byte {
+	if logWriter.timeFormat == TimeFormatNone || t.IsZero() {
+		return b
+	}
+	if logWriter.utc {
+		t = t.UTC()
+	}
+	switch logWriter.timeFormat {
+	case "":
+		b = t.AppendFormat(b, TimeFormatRFC3339Nano)
+	case TimeFormatUnix:
+		b = strconv.AppendInt(b, t.Unix(), 10)
+	case TimeFormatUnixMilli:
+		b = strconv.AppendInt(b, t.UnixNano()/int64(time.Millisecond), 10)
+	default:
+		b = t.AppendFormat(b, logWriter.timeFormat)
+	}
+	return append(b, '\t')
+}
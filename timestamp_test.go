@@ -0,0 +1,59 @@
+package lw
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeFormats(t *testing.T) {
+	defer DisableAndReset()
+	fixed := time.Date(2020, 5, 26, 14, 30, 15, 123456789, time.FixedZone("MDT", -6*60*60))
+
+	tests := []struct {
+		format string
+		utc    bool
+		want   string
+	}{
+		{"", false, "INFO:\t2020-05-26T14:30:15.123456789-06:00\tmsg\n"},
+		{TimeFormatRFC3339Nano, true, "INFO:\t2020-05-26T20:30:15.123456789Z\tmsg\n"},
+		{TimeFormatRFC3339, false, "INFO:\t2020-05-26T14:30:15-06:00\tmsg\n"},
+		{TimeFormatUnix, false, "INFO:\t1590525015\tmsg\n"},
+		{TimeFormatUnixMilli, false, "INFO:\t1590525015123\tmsg\n"},
+		{TimeFormatNone, false, "INFO:\tmsg\n"},
+		{"2006/01/02 15:04", true, "INFO:\t2020/05/26 20:30\tmsg\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		InitWithSettings(LogWriterState{
+			Enabled:     true,
+			InfoEnabled: true,
+			TimeFormat:  tt.format,
+			UTC:         tt.utc,
+			Clock:       func() time.Time { return fixed },
+		}, &buf)
+		Info("msg")
+		if buf.String() != tt.want {
+			t.Errorf("format %q utc %v: got %q, want %q", tt.format, tt.utc, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	defer DisableAndReset()
+	var buf bytes.Buffer
+	Enable(false, false, &buf)
+	WarningEnable(true)
+	SetTimeFormat(TimeFormatUnix)
+	SetClock(func() time.Time { return time.Unix(42, 0) })
+	Warning("msg")
+	if want := "WARNING:\t42\tmsg\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	SetClock(nil)
+	if GetState().Clock != nil {
+		t.Error("expected SetClock(nil) to restore the default clock")
+	}
+}